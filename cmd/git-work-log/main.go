@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata" // 内置时区数据库，在没有安装tzdata的容器中--tz也可以使用
+	"unicode/utf8"
 
 	"github.com/kway-teow/git-work-log/internal/ai"
 	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
 	"github.com/kway-teow/git-work-log/internal/report"
+	"github.com/kway-teow/git-work-log/internal/structured"
 	"github.com/spf13/cobra"
 )
 
@@ -22,19 +30,44 @@ var (
 
 var (
 	// 命令行参数
-	fromDate     string
-	toDate       string
-	outputFormat string
-	outputFile   string
-	repoPath     string // Git仓库路径
-	reposPath    string // 仓库目录路径，分析该目录下的所有Git仓库
-	modelName    string // Gemini模型名称
-	authorName   string // Git作者名称
-	timeRange    string // 时间范围类型：day(天)、week(周)、month(月)、year(年)
-	customDate   string // 指定具体日期 (YYYY-MM-DD 格式)
-	promptType   string // 提示词类型：basic(基础)、detailed(详细)、targeted(针对性) 或自定义提示词文件路径 (如: kpi.md 或 /path/to/custom.txt)
+	fromDate        string
+	toDate          string
+	outputFormat    string
+	outputFile      string
+	reportTemplate  string        // 自定义报告模板文件路径，指定后忽略--format
+	csvDailyFile    string        // CSV格式时按天汇总的表格的输出路径
+	repoPath        string        // Git仓库路径
+	gitBackend      string        // 读取Git仓库的后端：exec(git命令)或go-git
+	reposPath       string        // 仓库目录路径，分析该目录下的所有Git仓库
+	modelName       string        // AI模型名称
+	providerName    string        // AI提供方名称，如gemini、openai、ollama
+	baseURL         string        // AI服务的API基础地址（用于OpenAI兼容服务和Ollama）
+	apiKeyEnv       string        // 保存API密钥的环境变量名
+	chunkStrategy   string        // 提示词超出上限时的分块策略：auto、repo、week、none
+	maxPromptTokens int           // 单个提示词的token上限，0表示使用提供方的默认值
+	aiTimeout       time.Duration // 单次AI请求的超时时间，0表示使用提供方的默认值
+	aiRetries       int           // AI请求遇到临时错误时的最大重试次数
+	streamOutput    bool          // 是否在生成过程中实时输出AI摘要
+	noCache         bool          // 是否禁用AI摘要缓存
+	structuredMode  bool          // 是否以JSON模式生成结构化的AI摘要
+	authorNames     []string      // 筛选的Git作者名称、邮箱或正则表达式，可以指定多次
+	timeRange       string        // 时间范围类型：day(天)、week(周)、month(月)、year(年)
+	customDate      string        // 指定具体日期 (YYYY-MM-DD 格式)
+	timeZone        string        // 报告使用的时区，如Asia/Shanghai，为空时使用本地时区
+	dateField       string        // 筛选、排序和显示提交使用的日期：author(作者日期)或committer(提交日期)
+	promptsDir      string        // 提示词目录，其中的文件优先于用户配置目录、仓库内的提示词和内置提示词
+	language        string        // 报告、命令行消息和预设提示词的语言：zh或en
+	promptBase      string        // prompts new 使用的内置提示词
+	promptForce     bool          // prompts new 是否覆盖已存在的文件
+	promptType      string        // 提示词类型：basic(基础)、detailed(详细)、targeted(针对性) 或自定义提示词文件路径 (如: kpi.md 或 /path/to/custom.txt)
 )
 
+// reportLocation 报告使用的时区，由--tz参数设置
+var reportLocation = time.Local
+
+// reportDateField 筛选、排序和显示提交使用的日期字段，由--date-field参数设置
+var reportDateField = git.DateFieldAuthor
+
 // rootCmd 表示根命令
 
 var rootCmd = &cobra.Command{
@@ -42,13 +75,43 @@ var rootCmd = &cobra.Command{
 	Short: "基于Git提交记录自动生成报告",
 	Long: `git-work-log 是一个基于Git提交记录自动生成报告的工具。
 
-它使用Google Gemini AI对提交记录进行智能总结，生成格式化的报告。
+它使用AI（默认为Google Gemini）对提交记录进行智能总结，生成格式化的报告。
+可通过--provider选择不同的AI提供方。
 支持多种时间范围：天(day)、周(week)、月(month)、年(year)或自定义日期。
 支持单个仓库分析(--repo)或目录下所有仓库分析(--repos)。
 默认生成本周的报告。`,
-	Run: func(_ *cobra.Command, _ []string) {
+	// 错误由main统一打印一次，不再由cobra重复打印错误和用法
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		// 设置报告、命令行消息和预设提示词的语言
+		lang, err := i18n.Parse(language)
+		if err != nil {
+			return err
+		}
+		i18n.SetLanguage(lang)
+
+		// 按 --prompts-dir、用户配置目录、仓库内的.git-work-log/prompts 的顺序查找提示词
+		promptRepo := repoPath
+		if promptRepo == "" {
+			promptRepo = "."
+		}
+		ai.SetPromptDirs(ai.PromptDirs(promptsDir, promptRepo))
+
+		// 时间范围和报告中的日期使用的时区
+		if reportLocation, err = loadLocation(timeZone); err != nil {
+			return err
+		}
+		if reportDateField, err = git.ParseDateField(dateField); err != nil {
+			return err
+		}
+
+		// 选择读取Git仓库的后端
+		return git.SetBackend(git.Backend(gitBackend))
+	},
+	Run: func(cmd *cobra.Command, _ []string) {
 		// 执行生成报告的操作
-		generateReport()
+		generateReport(cmd)
 	},
 }
 
@@ -58,9 +121,128 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "显示版本信息",
 	Run: func(_ *cobra.Command, _ []string) {
-		fmt.Printf("git-work-log 版本: %s\n", version)
-		fmt.Printf("提交哈希: %s\n", commit)
-		fmt.Printf("构建日期: %s\n", date)
+		fmt.Printf(i18n.T("git-work-log 版本: %s\n"), version)
+		fmt.Printf(i18n.T("提交哈希: %s\n"), commit)
+		fmt.Printf(i18n.T("构建日期: %s\n"), date)
+	},
+}
+
+// 提示词预览子命令
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "预览发送给AI的提示词，不调用任何AI服务",
+	Long: `收集提交记录并构建完整的提示词，打印提示词内容、大小和估算的token数量，不调用任何AI服务。
+
+可用于调试自定义提示词文件，以及在将提交记录发送给外部服务之前检查会发送哪些数据。
+支持与生成报告相同的时间范围、仓库、作者和--prompt参数，指定--output时将提示词写入文件。`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		previewPrompt(cmd.Context())
+	},
+}
+
+// 提示词库管理子命令
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "管理提示词模板",
+	Long: `管理内置的提示词和提示词目录中的自定义提示词。
+
+提示词按 --prompts-dir、用户配置目录下的git-work-log/prompts、仓库内的.git-work-log/prompts 的顺序查找，
+其中与预设提示词同名的文件（basic.txt、detailed.txt、targeted.txt）会覆盖内置提示词。`,
+}
+
+// 列出提示词子命令
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有可用的提示词",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		templates, err := ai.ListPrompts(i18n.Current())
+		if err != nil {
+			return err
+		}
+		for _, template := range templates {
+			kind := i18n.T("自定义")
+			if template.Preset {
+				kind = i18n.T("预设")
+			}
+			fmt.Printf("%-12s %-6s %s\n", template.Name, kind, template.Source)
+		}
+		return nil
+	},
+}
+
+// 显示提示词子命令
+var promptsShowCmd = &cobra.Command{
+	Use:   "show <名称或文件路径>",
+	Short: "显示提示词的内容和来源",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		content, source, err := ai.ResolvePrompt(ai.GetPromptTypeFromString(args[0]), i18n.Current())
+		if err != nil {
+			return err
+		}
+		fmt.Printf(i18n.T("# 来源: %s\n"), source)
+		fmt.Print(content)
+		if !strings.HasSuffix(content, "\n") {
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// 新建提示词子命令
+var promptsNewCmd = &cobra.Command{
+	Use:   "new <名称>",
+	Short: "以内置提示词为基础在用户配置目录中新建提示词",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return newPrompt(args[0])
+	},
+}
+
+// 校验提示词子命令
+var promptsValidateCmd = &cobra.Command{
+	Use:   "validate [名称或文件路径...]",
+	Short: "校验提示词的模板语法和变量，未指定时校验所有提示词",
+	RunE: func(_ *cobra.Command, args []string) error {
+		return validatePrompts(args)
+	},
+}
+
+// 缓存管理子命令
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理AI摘要缓存",
+}
+
+// 清空缓存子命令
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "删除所有缓存的AI摘要",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cacheDir, err := ai.DefaultCacheDir()
+		if err != nil {
+			return err
+		}
+		removed, err := ai.NewCache(cacheDir).Clear()
+		if err != nil {
+			return err
+		}
+		i18n.Printf("已删除 %d 条缓存的AI摘要 (%s)\n", removed, cacheDir)
+		return nil
+	},
+}
+
+// 显示缓存目录子命令
+var cacheDirCmd = &cobra.Command{
+	Use:   "dir",
+	Short: "显示AI摘要缓存目录",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cacheDir, err := ai.DefaultCacheDir()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cacheDir)
+		return nil
 	},
 }
 
@@ -68,31 +250,65 @@ func init() {
 	// 添加版本子命令
 	rootCmd.AddCommand(versionCmd)
 
+	// 添加提示词预览子命令
+	rootCmd.AddCommand(promptCmd)
+
+	// 添加提示词库管理子命令
+	promptsNewCmd.Flags().StringVar(&promptBase, "base", string(ai.BasicPrompt), "作为基础的内置提示词 (basic, detailed, targeted)")
+	promptsNewCmd.Flags().BoolVar(&promptForce, "force", false, "覆盖已存在的提示词文件")
+	promptsCmd.AddCommand(promptsListCmd, promptsShowCmd, promptsNewCmd, promptsValidateCmd)
+	rootCmd.AddCommand(promptsCmd)
+
+	// 添加缓存管理子命令
+	cacheCmd.AddCommand(cacheClearCmd, cacheDirCmd)
+	rootCmd.AddCommand(cacheCmd)
+
 	// 添加命令行参数
 	rootCmd.PersistentFlags().StringVar(&fromDate, "from", "", "开始日期 (YYYY-MM-DD 格式)，与--range和--date参数互斥")
 	rootCmd.PersistentFlags().StringVar(&toDate, "to", "", "结束日期 (YYYY-MM-DD 格式)，与--range和--date参数互斥")
 	rootCmd.PersistentFlags().StringVar(&timeRange, "range", "week", "时间范围 (day, week, month, year)，默认为week")
+	rootCmd.PersistentFlags().StringVar(&timeZone, "tz", "", "报告使用的时区，如Asia/Shanghai、UTC (默认为本地时区)，用于计算时间范围和显示提交日期")
+	rootCmd.PersistentFlags().StringVar(&dateField, "date-field", string(git.DateFieldAuthor), "筛选、排序和显示提交使用的日期 (author=作者日期, committer=提交日期，rebase或cherry-pick后会更新)")
 	rootCmd.PersistentFlags().StringVar(&customDate, "date", "", "指定具体日期 (YYYY-MM-DD 格式)，与--range、--from和--to参数互斥")
-	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "报告格式 (text 或 markdown)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "报告格式 (text, markdown, json, html 或 csv)")
+	rootCmd.PersistentFlags().StringVar(&reportTemplate, "template", "", "自定义报告模板文件路径 (Go模板语法，.html文件自动转义HTML)，指定后忽略--format")
+	rootCmd.PersistentFlags().StringVar(&csvDailyFile, "csv-daily", "", "使用--format csv时，将按日期和仓库汇总的工时表格写入该文件")
 	rootCmd.PersistentFlags().StringVar(&outputFile, "output", "", "输出文件路径 (默认为标准输出)")
 	rootCmd.PersistentFlags().StringVar(&repoPath, "repo", "", "Git仓库路径 (默认为当前目录)")
+	rootCmd.PersistentFlags().StringVar(&gitBackend, "git-backend", string(git.BackendExec), fmt.Sprintf("读取Git仓库的后端 (%s)，go-git不需要安装git命令", strings.Join(git.Backends(), ", ")))
 	rootCmd.PersistentFlags().StringVar(&reposPath, "repos", "", "仓库目录路径，分析该目录下的所有Git仓库")
-	rootCmd.PersistentFlags().StringVar(&modelName, "model", "", "Gemini模型名称 (默认为gemini-2.5-flash-preview-05-20)")
-	rootCmd.PersistentFlags().StringVar(&authorName, "author", "", "Git作者名称")
+	rootCmd.PersistentFlags().StringVar(&providerName, "provider", ai.DefaultProvider, fmt.Sprintf("AI提供方 (%s)，未指定且默认提供方不可用时使用heuristic离线摘要", strings.Join(ai.Providers(), ", ")))
+	rootCmd.PersistentFlags().StringVar(&baseURL, "base-url", "", "AI服务的API基础地址 (openai默认为https://api.openai.com/v1，ollama默认为http://localhost:11434)")
+	rootCmd.PersistentFlags().StringVar(&apiKeyEnv, "api-key-env", "", "保存API密钥的环境变量名 (openai默认为OPENAI_API_KEY)")
+	rootCmd.PersistentFlags().StringVar(&chunkStrategy, "chunk-strategy", string(ai.ChunkAuto), "提示词超出上限时的分块策略 (auto=按token切分, repo=按仓库, week=按周, none=不分块)")
+	rootCmd.PersistentFlags().IntVar(&maxPromptTokens, "max-prompt-tokens", 0, "单个提示词的token上限，超出时分段总结后再合并 (默认使用提供方的默认值)")
+	rootCmd.PersistentFlags().DurationVar(&aiTimeout, "ai-timeout", 0, "单次AI请求的超时时间，如90s、5m (默认gemini和openai为2m，ollama为10m)")
+	rootCmd.PersistentFlags().IntVar(&aiRetries, "ai-retries", ai.DefaultMaxRetries, "AI请求遇到限流(429)、服务端错误(5xx)或超时时的最大重试次数")
+	rootCmd.PersistentFlags().BoolVar(&streamOutput, "stream", false, "在生成过程中实时将AI摘要输出到终端，最终摘要仍会写入报告")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "不使用AI摘要缓存，总是重新生成")
+	rootCmd.PersistentFlags().BoolVar(&structuredMode, "structured", false, "以JSON模式生成结构化的AI摘要（概述、成果、进行中、风险、下一步），按固定栏目渲染")
+	rootCmd.PersistentFlags().StringVar(&modelName, "model", "", "AI模型名称 (默认使用提供方的默认模型，Gemini为gemini-2.5-flash-preview-05-20)")
+	rootCmd.PersistentFlags().StringArrayVar(&authorNames, "author", nil, "筛选的Git作者名称、邮箱或正则表达式，可以指定多次，匹配任意一个即可 (默认为当前用户的user.name和user.email)")
+	rootCmd.PersistentFlags().StringVar(&language, "lang", string(i18n.Chinese), "报告、命令行消息和预设提示词的语言 (zh=中文, en=英文)")
+	rootCmd.PersistentFlags().StringVar(&promptsDir, "prompts-dir", "", "提示词目录，其中的basic.txt等文件覆盖内置提示词，其他文件可按名称用于--prompt")
 	rootCmd.PersistentFlags().StringVar(&promptType, "prompt", "basic", "提示词类型 (basic=基础, detailed=详细, targeted=针对性) 或自定义提示词文件路径 (如: kpi.md 或 /path/to/custom.txt)")
 }
 
 func main() {
+	// 按Ctrl-C时取消正在进行的AI请求
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// 执行根命令
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		i18n.Printf("错误: %v\n", err)
 		os.Exit(1)
 	}
 }
 
 // calculateTimeRange 根据时间范围类型计算开始和结束日期
 func calculateTimeRange(rangeType string) (time.Time, time.Time) {
-	now := time.Now()
+	now := time.Now().In(reportLocation)
 	var from, to time.Time
 
 	switch rangeType {
@@ -121,23 +337,320 @@ func calculateTimeRange(rangeType string) (time.Time, time.Time) {
 	return from, to
 }
 
+// newAIConfig 根据命令行参数创建AI后端的配置
+func newAIConfig() (ai.Config, error) {
+	// 解析分块策略
+	strategy, err := ai.ParseChunkStrategy(chunkStrategy)
+	if err != nil {
+		return ai.Config{}, err
+	}
+	return ai.Config{
+		ModelName:       modelName,
+		BaseURL:         baseURL,
+		APIKeyEnv:       apiKeyEnv,
+		ChunkStrategy:   strategy,
+		MaxPromptTokens: maxPromptTokens,
+		Timeout:         aiTimeout,
+		MaxRetries:      aiRetries,
+	}, nil
+}
+
 // generateReport 生成报告
-func generateReport() {
-	// 检查环境变量
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		fmt.Println("错误: 未设置GEMINI_API_KEY环境变量")
+func generateReport(cmd *cobra.Command) {
+	aiConfig, err := newAIConfig()
+	if err != nil {
+		i18n.Printf("错误: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 创建Gemini客户端
-	geminiClient, err := ai.NewGeminiClientWithModel(modelName)
+	// 按天汇总的表格只在直接输出CSV时生成，其他情况下报错而不是静默忽略
+	if csvDailyFile != "" && (report.Format(outputFormat) != report.FormatCSV || reportTemplate != "") {
+		i18n.Println("错误: --csv-daily 需要 --format csv，且不能与 --template 同时使用")
+		os.Exit(1)
+	}
+
+	// 创建AI摘要后端，在分析仓库之前创建以便尽早发现配置错误
+	summarizer, err := ai.NewSummarizer(providerName, aiConfig)
+	if err != nil {
+		// 未显式指定提供方时（如没有设置GEMINI_API_KEY），退回到离线的规则摘要
+		if cmd.Flags().Changed("provider") {
+			i18n.Printf("错误: 创建AI客户端失败: %v\n", err)
+			os.Exit(1)
+		}
+		i18n.Printf("警告: 创建AI客户端失败: %v，使用离线规则摘要\n", err)
+		summarizer = ai.NewHeuristicSummarizer()
+	}
+	defer summarizer.Close()
+
+	// 在加上缓存之前判断提供方是否支持结构化输出
+	structuredSummarizer, supportsStructured := summarizer.(ai.StructuredSummarizer)
+	if structuredMode && !supportsStructured {
+		i18n.Println("警告: 当前AI提供方不支持结构化输出，使用普通摘要")
+	}
+
+	// 为AI摘要加上磁盘缓存，离线规则摘要本身就很快，不需要缓存
+	if _, isHeuristic := summarizer.(*ai.HeuristicSummarizer); !noCache && !isHeuristic {
+		if cacheDir, err := ai.DefaultCacheDir(); err != nil {
+			i18n.Printf("警告: %v，不使用AI摘要缓存\n", err)
+		} else {
+			cached := ai.NewCachedSummarizer(summarizer, ai.NewCache(cacheDir), strings.ToLower(providerName), aiConfig)
+			summarizer = cached
+			// 结构化摘要同样经过缓存
+			if supportsStructured {
+				structuredSummarizer = cached
+			}
+		}
+	}
+
+	// 在收集提交记录之前校验提示词，避免运行到一半才发现模板错误
+	if err := ai.ValidatePrompt(ai.GetPromptTypeFromString(promptType), i18n.Current()); err != nil {
+		i18n.Printf("错误: 提示词无效: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 同样提前加载自定义报告模板
+	var customTemplate *report.Template
+	if reportTemplate != "" {
+		var err error
+		if customTemplate, err = report.LoadTemplate(reportTemplate); err != nil {
+			i18n.Printf("错误: 报告模板无效: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// 确定时间范围并收集所有仓库的提交记录
+	ctx := cmd.Context()
+	from, to := resolveTimeRange()
+	allCommits := collectCommits(ctx, from, to)
+	if len(allCommits) == 0 {
+		return
+	}
+
+	i18n.Printf("正在生成报告...\n")
+
+	// 报告不包含AI总结时（如没有使用自定义模板的CSV）不调用AI服务
+	reportFormat := report.Format(outputFormat)
+	var reportSummary string
+	var structuredSummary *structured.Summary
+	if customTemplate != nil || reportFormat.UsesSummary() {
+		// 使用AI生成报告
+		i18n.Println("使用AI生成摘要...")
+
+		// 根据选择的提示词类型确定使用哪种提示词
+		aiPromptType := selectPromptType()
+		info := newPromptInfo(from, to)
+
+		if structuredMode && supportsStructured {
+			structuredSummary, err = summarizeStructured(ctx, structuredSummarizer, allCommits, aiPromptType, info)
+			if err == nil {
+				reportSummary = structuredSummary.Markdown(i18n.Current())
+			}
+		} else {
+			reportSummary, err = summarize(ctx, summarizer, allCommits, aiPromptType, info)
+		}
+		if err != nil {
+			exitIfCanceled(ctx)
+			i18n.Printf("错误: 生成报告摘要失败: %v\n", err)
+			return
+		}
+	}
+
+	// 离线规则摘要等不检查ctx，在写入报告之前确认没有被取消
+	exitIfCanceled(ctx)
+
+	// 决定输出目标
+	var output io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("创建输出文件失败: %v\n"), err)
+			return
+		}
+		defer file.Close()
+		output = file
+	}
+
+	// 创建报告生成器
+	reportGenerator := report.NewGenerator(reportFormat, output)
+	reportGenerator.Structured = structuredSummary
+	reportGenerator.Language = i18n.Current()
+	reportGenerator.Template = customTemplate
+
+	// CSV格式可以额外输出按天汇总的表格
+	if csvDailyFile != "" {
+		dailyFile, err := os.Create(csvDailyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("创建输出文件失败: %v\n"), err)
+			return
+		}
+		defer dailyFile.Close()
+		reportGenerator.DailyOutput = dailyFile
+	}
+
+	// 生成并输出报告
+	err = reportGenerator.GenerateReport(reportSummary, allCommits, from, to)
+	if err != nil {
+		i18n.Printf("错误: 输出报告失败: %v\n", err)
+		return
+	}
+
+	// 根据时间范围类型显示不同的完成消息
+	reportType := i18n.T(getReportTypeShort())
+
+	i18n.Printf("%s生成完成！\n", reportType)
+}
+
+// previewPrompt 收集提交记录，按照与生成报告相同的方式构建提示词，
+// 打印完整的提示词、大小、估算的token数量和是否需要分段总结，不调用任何AI服务
+func previewPrompt(ctx context.Context) {
+	aiConfig, err := newAIConfig()
 	if err != nil {
-		fmt.Printf("错误: 创建Gemini客户端失败: %v\n", err)
+		i18n.Printf("错误: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 判断使用何种时间范围
+	from, to := resolveTimeRange()
+	allCommits := collectCommits(ctx, from, to)
+	if len(allCommits) == 0 {
+		return
+	}
+
+	aiPromptType := selectPromptType()
+	if _, source, err := ai.ResolvePrompt(aiPromptType, i18n.Current()); err == nil {
+		i18n.Printf("提示词来源: %s\n", source)
+	}
+	preview, err := ai.PreviewPrompt(allCommits, aiPromptType, newPromptInfo(from, to), providerName, aiConfig, structuredMode)
+	if err != nil {
+		i18n.Printf("错误: 构建提示词失败: %v\n", err)
+		return
+	}
+	prompt := preview.Prompt
+
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, []byte(prompt), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("写入提示词文件失败: %v\n"), err)
+			return
+		}
+		i18n.Printf("已将提示词写入: %s\n", outputFile)
+	} else {
+		i18n.Println("---------- 提示词 ----------")
+		fmt.Println(prompt)
+		fmt.Fprintln(os.Stderr, "----------------------------")
+	}
+
+	i18n.Printf("提示词大小: %d 字节, %d 个字符, 约 %d tokens\n", len(prompt), utf8.RuneCountInString(prompt), ai.EstimateTokens(prompt))
+	if preview.Chunks > 0 {
+		i18n.Printf("提示: 超过单个提示词的上限 %d tokens，生成报告时会按--chunk-strategy分为 %d 段分别总结，以上为不分段时的提示词\n", preview.MaxTokens, preview.Chunks)
+	}
+}
+
+// newPrompt 将内置提示词复制到--prompts-dir或用户配置目录中，作为新提示词的起点
+func newPrompt(name string) error {
+	base := ai.GetPromptTypeFromString(promptBase)
+	if ai.IsCustomPrompt(base) {
+		return fmt.Errorf("未知的内置提示词: %s (可选: basic, detailed, targeted)", promptBase)
+	}
+	content, err := ai.BuiltinPrompt(base, i18n.Current())
+	if err != nil {
+		return err
+	}
+
+	dir := promptsDir
+	if dir == "" {
+		if dir, err = ai.UserPromptDir(); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("创建提示词目录失败: %w", err)
+	}
+
+	if filepath.Ext(name) == "" {
+		name += ".md"
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil && !promptForce {
+		return fmt.Errorf("提示词文件已存在: %s (使用--force覆盖)", path)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("写入提示词文件失败: %w", err)
+	}
+
+	i18n.Printf("已创建提示词: %s\n", path)
+	i18n.Printf("编辑后使用 --prompt %s 生成报告\n", strings.TrimSuffix(name, filepath.Ext(name)))
+	return nil
+}
+
+// validatePrompts 校验指定的提示词，未指定时校验提示词库中的所有提示词
+func validatePrompts(names []string) error {
+	if len(names) == 0 {
+		templates, err := ai.ListPrompts(i18n.Current())
+		if err != nil {
+			return err
+		}
+		for _, template := range templates {
+			names = append(names, template.Name)
+		}
+	}
+
+	failed := 0
+	for _, name := range names {
+		content, source, err := ai.ResolvePrompt(ai.GetPromptTypeFromString(name), i18n.Current())
+		if err == nil {
+			err = ai.ValidatePromptTemplate(content)
+		}
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("✓ %s (%s)\n", name, source)
+		if !ai.ReferencesCommits(content) {
+			i18n.Printf("  提示: 没有引用{{.CommitMessages}}或{{.Commits}}，提交记录将追加在末尾\n")
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d 个提示词校验失败", failed)
+	}
+	return nil
+}
+
+// newPromptInfo 返回报告的时间范围、作者等元信息，用于渲染提示词模板
+func newPromptInfo(from, to time.Time) ai.PromptInfo {
+	return ai.PromptInfo{
+		From:     from,
+		To:       to,
+		Author:   strings.Join(authorNames, ", "),
+		Language: i18n.Current(),
+	}
+}
+
+// selectPromptType 解析--prompt参数并打印使用的提示词
+func selectPromptType() ai.PromptType {
+	aiPromptType := ai.GetPromptTypeFromString(promptType)
+
+	if ai.IsCustomPrompt(aiPromptType) {
+		i18n.Printf("使用自定义提示词文件: %s\n", promptType)
+	} else {
+		switch aiPromptType {
+		case ai.BasicPrompt:
+			i18n.Println("使用基础提示词生成报告")
+		case ai.DetailedPrompt:
+			i18n.Println("使用详细提示词生成报告")
+		case ai.TargetedPrompt:
+			i18n.Println("使用针对性提示词生成报告")
+		default:
+			i18n.Println("使用默认基础提示词生成报告")
+		}
+	}
+
+	return aiPromptType
+}
+
+// resolveTimeRange 根据--from/--to、--date或--range参数确定报告的时间范围
+func resolveTimeRange() (time.Time, time.Time) {
 	var from, to time.Time
 	var err1, err2 error
 
@@ -145,33 +658,57 @@ func generateReport() {
 	case fromDate != "" && toDate != "":
 		// 使用自定义时间范围（从某天到某天）
 		// 解析指定的日期范围
-		from, err1 = time.Parse("2006-01-02", fromDate)
-		to, err2 = time.Parse("2006-01-02", toDate)
+		from, err1 = time.ParseInLocation("2006-01-02", fromDate, reportLocation)
+		to, err2 = time.ParseInLocation("2006-01-02", toDate, reportLocation)
 		if err1 != nil || err2 != nil {
-			fmt.Println("错误: 日期格式不正确，请使用YYYY-MM-DD格式")
+			i18n.Println("错误: 日期格式不正确，请使用YYYY-MM-DD格式")
 			os.Exit(1)
 		}
 		// 调整结束日期为当天结束
-		to = to.Add(24*time.Hour - time.Second)
-		fmt.Printf("使用自定义时间范围: %s 到 %s\n", fromDate, toDate)
+		to = endOfDay(to)
+		i18n.Printf("使用自定义时间范围: %s 到 %s\n", fromDate, toDate)
 	case customDate != "":
 		// 使用指定日期
 		// 解析指定的日期
-		specificDate, err := time.Parse("2006-01-02", customDate)
+		specificDate, err := time.ParseInLocation("2006-01-02", customDate, reportLocation)
 		if err != nil {
-			fmt.Println("错误: 日期格式不正确，请使用YYYY-MM-DD格式")
+			i18n.Println("错误: 日期格式不正确，请使用YYYY-MM-DD格式")
 			os.Exit(1)
 		}
-		// 设置为指定日期的0点到次日0点
-		from = time.Date(specificDate.Year(), specificDate.Month(), specificDate.Day(), 0, 0, 0, 0, specificDate.Location())
-		to = from.AddDate(0, 0, 1)
-		fmt.Printf("使用指定日期: %s\n", customDate)
+		// 设置为指定日期的0点到当天结束，不包含次日0点的提交
+		from = specificDate
+		to = endOfDay(specificDate)
+		i18n.Printf("使用指定日期: %s\n", customDate)
 	default:
 		// 使用预定义的时间范围
 		from, to = calculateTimeRange(timeRange)
-		fmt.Printf("使用预定义时间范围 %s: %s 到 %s\n", timeRange, from.Format("2006-01-02"), to.Format("2006-01-02 15:04"))
+		i18n.Printf("使用预定义时间范围 %s: %s 到 %s\n", timeRange, from.Format("2006-01-02"), to.Format("2006-01-02 15:04"))
 	}
 
+	return from, to
+}
+
+// endOfDay 返回日期当天的最后一刻，即次日0点的前一纳秒
+func endOfDay(date time.Time) time.Time {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return midnight.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// loadLocation 解析--tz参数，为空或Local时使用本地时区
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区 %s: %w", name, err)
+	}
+	return location, nil
+}
+
+// collectCommits 收集--repo或--repos指定的所有仓库在时间范围内的提交记录并打印统计信息，
+// 没有找到任何提交时返回nil
+func collectCommits(ctx context.Context, from, to time.Time) []git.CommitInfo {
 	// 判断使用何种分析模式：单仓库还是多仓库
 	var repoPaths []string
 	var discoveryErr error
@@ -181,13 +718,13 @@ func generateReport() {
 		// 多仓库模式：发现指定目录下的所有Git仓库
 		repoPaths, discoveryErr = git.DiscoverGitRepos(reposPath)
 		if discoveryErr != nil {
-			fmt.Printf("错误: 发现Git仓库失败: %v\n", discoveryErr)
-			return
+			i18n.Printf("错误: 发现Git仓库失败: %v\n", discoveryErr)
+			return nil
 		}
 
 		if len(repoPaths) == 0 {
-			fmt.Printf("在目录 %s 下没有发现任何Git仓库\n", reposPath)
-			return
+			i18n.Printf("在目录 %s 下没有发现任何Git仓库\n", reposPath)
+			return nil
 		}
 	case repoPath != "":
 		// 单仓库模式：使用指定的仓库路径
@@ -201,42 +738,48 @@ func generateReport() {
 	var allCommits []git.CommitInfo
 	repoCommitCounts := make(map[string]int)
 
-	fmt.Printf("\n处理 %d 个仓库:\n", len(repoPaths))
+	i18n.Printf("\n处理 %d 个仓库:\n", len(repoPaths))
 
 	for _, currentRepoPath := range repoPaths {
-		fmt.Printf("正在分析仓库: %s\n", currentRepoPath)
+		exitIfCanceled(ctx)
+		i18n.Printf("正在分析仓库: %s\n", currentRepoPath)
 
 		// 创建Git选项
 		gitOpts := git.NewGitOptions(currentRepoPath)
+		gitOpts.DateField = reportDateField
 
-		// 如果命令行指定了作者名称，覆盖自动检测的用户名
-		if authorName != "" {
-			gitOpts.Author = authorName
+		// 如果命令行指定了作者，覆盖自动检测的当前用户身份
+		if len(authorNames) > 0 {
+			gitOpts.Authors = authorNames
 		}
 
 		// 获取提交记录
-		commits, commitErr := git.GetCommitsBetween(from, to, gitOpts)
+		commits, commitErr := git.GetCommitsBetween(ctx, from, to, gitOpts)
 		if commitErr != nil {
-			fmt.Printf("  警告: 仓库 %s 获取Git提交记录失败: %v\n", currentRepoPath, commitErr)
+			exitIfCanceled(ctx)
+			i18n.Printf("  警告: 仓库 %s 获取Git提交记录失败: %v\n", currentRepoPath, commitErr)
 			continue
 		}
 
 		// 记录每个仓库的提交数量
 		repoCommitCounts[currentRepoPath] = len(commits)
 
-		// 为每个提交添加仓库信息
+		// 为每个提交添加仓库信息，日期转换为报告的时区
 		for i := range commits {
 			commits[i].RepoPath = currentRepoPath
+			commits[i].Date = commits[i].Date.In(reportLocation)
+			commits[i].AuthorDate = commits[i].AuthorDate.In(reportLocation)
+			commits[i].CommitterDate = commits[i].CommitterDate.In(reportLocation)
 		}
 
 		// 合并到总的提交列表
 		allCommits = append(allCommits, commits...)
 
-		fmt.Printf("  找到 %d 条提交记录\n", len(commits))
+		i18n.Printf("  找到 %d 条提交记录\n", len(commits))
 	}
 
 	// 显示汇总统计信息
-	fmt.Printf("\n=== 提交记录统计 ===\n")
+	i18n.Printf("\n=== 提交记录统计 ===\n")
 	totalCommits := 0
 	for repoPath, count := range repoCommitCounts {
 		// 显示相对路径，更清晰
@@ -246,90 +789,60 @@ func generateReport() {
 				displayPath = rel
 			}
 		}
-		fmt.Printf("  %s: %d 条提交\n", displayPath, count)
+		i18n.Printf("  %s: %d 条提交\n", displayPath, count)
 		totalCommits += count
 	}
-	fmt.Printf("总计: %d 条提交\n\n", totalCommits)
+	i18n.Printf("总计: %d 条提交\n\n", totalCommits)
 
 	if len(allCommits) == 0 {
-		fmt.Printf("指定时间范围 %s 到 %s 在所有仓库中都没有找到提交记录\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
-		return
+		i18n.Printf("指定时间范围 %s 到 %s 在所有仓库中都没有找到提交记录\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil
 	}
 
 	// 显示作者信息
-	if authorName != "" {
-		fmt.Printf("筛选作者: %s\n", authorName)
+	if len(authorNames) > 0 {
+		i18n.Printf("筛选作者: %s\n", strings.Join(authorNames, ", "))
 	} else {
-		fmt.Println("获取所有作者的提交")
+		i18n.Println("获取所有作者的提交")
 	}
 
-	fmt.Printf("正在生成报告...\n")
-
-	// 使用AI生成报告
-	fmt.Println("使用AI生成摘要...")
-
-	// 重用之前创建的客户端变量
-	if geminiClient == nil {
-		geminiClient, err = ai.NewGeminiClientWithModel(modelName)
-		if err != nil {
-			fmt.Printf("错误: 创建Gemini客户端失败: %v\n", err)
-			return
-		}
-	}
-	defer geminiClient.Close()
-
-	// 根据选择的提示词类型确定使用哪种提示词
-	aiPromptType := ai.GetPromptTypeFromString(promptType)
+	return allCommits
+}
 
-	if ai.IsCustomPrompt(aiPromptType) {
-		fmt.Printf("使用自定义提示词文件: %s\n", promptType)
-	} else {
-		switch aiPromptType {
-		case ai.BasicPrompt:
-			fmt.Println("使用基础提示词生成报告")
-		case ai.DetailedPrompt:
-			fmt.Println("使用详细提示词生成报告")
-		case ai.TargetedPrompt:
-			fmt.Println("使用针对性提示词生成报告")
-		default:
-			fmt.Println("使用默认基础提示词生成报告")
-		}
+// exitIfCanceled 收到中断信号（Ctrl-C或SIGTERM）后打印提示并以非零状态退出，不再输出报告
+func exitIfCanceled(ctx context.Context) {
+	if ctx.Err() != nil {
+		i18n.Println("已取消生成报告")
+		os.Exit(1)
 	}
+}
 
-	// 使用AI生成报告
-	reportSummary, err := geminiClient.SummarizeCommitsWithPrompt(allCommits, aiPromptType)
-	if err != nil {
-		fmt.Printf("错误: 生成报告摘要失败: %v\n", err)
-		return
+// summarize 生成AI摘要，启用--stream且提供方支持流式输出时，实时将摘要打印到标准错误，
+// 不影响输出到标准输出的报告
+func summarize(ctx context.Context, summarizer ai.Summarizer, commits []git.CommitInfo, promptType ai.PromptType, info ai.PromptInfo) (string, error) {
+	if !streamOutput {
+		return summarizer.SummarizeCommitsWithPrompt(ctx, commits, promptType, info)
 	}
 
-	// 决定输出目标
-	var output io.Writer = os.Stdout
-	if outputFile != "" {
-		file, err := os.Create(outputFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "创建输出文件失败: %v\n", err)
-			return
-		}
-		defer file.Close()
-		output = file
+	streamSummarizer, ok := summarizer.(ai.StreamSummarizer)
+	if !ok {
+		i18n.Println("提示: 当前AI提供方不支持流式输出")
+		return summarizer.SummarizeCommitsWithPrompt(ctx, commits, promptType, info)
 	}
 
-	// 创建报告生成器
-	reportFormat := report.Format(outputFormat)
-	reportGenerator := report.NewGenerator(reportFormat, output)
+	i18n.Println("\n---------- AI 摘要 ----------")
+	summary, err := streamSummarizer.StreamCommitsWithPrompt(ctx, commits, promptType, info, os.Stderr)
+	fmt.Fprintln(os.Stderr, "\n-----------------------------")
+	return summary, err
+}
 
-	// 生成并输出报告
-	err = reportGenerator.GenerateReport(reportSummary, allCommits, from, to)
-	if err != nil {
-		fmt.Printf("错误: 输出报告失败: %v\n", err)
-		return
+// summarizeStructured 以JSON模式生成结构化摘要，结构化输出不支持流式输出
+func summarizeStructured(ctx context.Context, summarizer ai.StructuredSummarizer, commits []git.CommitInfo, promptType ai.PromptType, info ai.PromptInfo) (*structured.Summary, error) {
+	if streamOutput {
+		i18n.Println("提示: 结构化输出不支持流式输出")
 	}
-
-	// 根据时间范围类型显示不同的完成消息
-	reportType := getReportTypeShort()
-
-	fmt.Printf("%s生成完成！\n", reportType)
+	i18n.Println("使用JSON模式生成结构化摘要")
+	return summarizer.SummarizeCommitsStructured(ctx, commits, promptType, info)
 }
 
 // getReportTypeShort 获取报告类型的简短描述
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// runMainEnv 设置该环境变量时，测试二进制直接执行main函数，用于在子进程中运行命令
+const runMainEnv = "GIT_WORK_LOG_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain 在子进程中以指定参数运行命令，返回标准输出、标准错误和退出错误
+func runMain(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	return stdout, stderr, err
+}
+
+// TestJSONReportStdout 测试进度信息不混入标准输出，--format json的输出可以直接按JSON解析
+func TestJSONReportStdout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil || os.Getenv("SKIP_GIT_TESTS") == "true" {
+		t.Skip("git命令不可用，跳过测试")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=tester", "GIT_AUTHOR_EMAIL=tester@example.com", "GIT_AUTHOR_DATE=2025-05-20T10:00:00Z",
+			"GIT_COMMITTER_NAME=tester", "GIT_COMMITTER_EMAIL=tester@example.com", "GIT_COMMITTER_DATE=2025-05-20T10:00:00Z")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, output)
+		}
+	}
+	run("init", "-q")
+	run("commit", "-q", "--allow-empty", "-m", "feat: 添加周报")
+
+	stdout, stderr, err := runMain("--repo", dir, "--date", "2025-05-20", "--tz", "UTC", "--author", "tester",
+		"--provider", "heuristic", "--format", "json", "--stream", "--prompts-dir", t.TempDir())
+	if err != nil {
+		t.Fatalf("运行命令失败: %v\n%s", err, stderr.String())
+	}
+
+	var data struct {
+		Summary      string `json:"summary"`
+		TotalCommits int    `json:"total_commits"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &data); err != nil {
+		t.Fatalf("标准输出应只包含JSON报告: %v\n%s", err, stdout.String())
+	}
+	if data.TotalCommits != 1 || data.Summary == "" {
+		t.Errorf("报告内容不正确: %+v", data)
+	}
+	if stderr.Len() == 0 {
+		t.Error("进度信息应输出到标准错误")
+	}
+}
+
+// TestCommandErrorPrintedOnce 测试参数错误只打印一次，不附带用法说明
+func TestCommandErrorPrintedOnce(t *testing.T) {
+	for _, args := range [][]string{{"--lang", "xx"}, {"--tz", "Nowhere/X"}, {"prompts", "validate", "不存在的提示词.md"}} {
+		_, stderr, err := runMain(args...)
+		if err == nil {
+			t.Errorf("%v: 应以非零状态退出", args)
+			continue
+		}
+		if count := strings.Count(stderr.String(), "错误"); count != 1 || strings.Contains(stderr.String(), "Usage:") {
+			t.Errorf("%v: 错误应只打印一次且不显示用法, 得到:\n%s", args, stderr.String())
+		}
+	}
+}
+
+// TestSignalCancelsScan 测试读取仓库时收到SIGTERM会停止运行，以非零状态退出且不输出报告。
+// 用一个在git log时等待的假git命令模拟读取很慢的仓库
+func TestSignalCancelsScan(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows不支持SIGTERM")
+	}
+
+	binDir := t.TempDir()
+	ready := filepath.Join(binDir, "ready")
+	script := "#!/bin/sh\ncase \"$1\" in\n  log) touch \"" + ready + "\"; exec sleep 30 ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(binDir, "git"), []byte(script), 0o755); err != nil {
+		t.Fatalf("写入假git命令失败: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0], "--repo", t.TempDir(), "--author", "tester", "--provider", "heuristic", "--format", "csv")
+	cmd.Env = append(os.Environ(), runMainEnv+"=1", "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("启动命令失败: %v", err)
+	}
+
+	// 等待开始读取仓库后发送信号
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(ready); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = cmd.Process.Kill()
+			t.Fatalf("没有开始读取仓库:\n%s", stderr.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	start := time.Now()
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("发送信号失败: %v", err)
+	}
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() == 0 {
+		t.Errorf("取消后应以非零状态退出, 得到: %v\n%s", err, stderr.String())
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("收到信号后应立即停止git命令, 用时: %v", elapsed)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("取消后不应输出报告, 得到: %q", stdout.String())
+	}
+}
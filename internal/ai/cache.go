@@ -0,0 +1,284 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/internal/structured"
+)
+
+// Cache 是AI摘要的磁盘缓存，每条摘要保存为缓存目录下的一个JSON文件
+type Cache struct {
+	dir string
+}
+
+// cacheEntry 缓存文件的内容
+type cacheEntry struct {
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	PromptType string    `json:"prompt_type"`
+	Commits    int       `json:"commits"`
+	CreatedAt  time.Time `json:"created_at"`
+	Summary    string    `json:"summary"`
+}
+
+// DefaultCacheDir 返回默认的缓存目录（用户缓存目录下的git-work-log/summaries）
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户缓存目录失败: %w", err)
+	}
+	return filepath.Join(cacheDir, "git-work-log", "summaries"), nil
+}
+
+// NewCache 创建使用指定目录的缓存，目录在第一次写入时创建
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir 返回缓存目录
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get 读取缓存的摘要
+func (c *Cache) Get(key string) (string, bool) {
+	content, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return "", false
+	}
+	return entry.Summary, true
+}
+
+// put 写入缓存，先写临时文件再重命名，避免中断时留下不完整的文件
+func (c *Cache) put(key string, entry cacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化缓存失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建缓存文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Clear 删除所有缓存的摘要，返回删除的条数
+func (c *Cache) Clear() (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("读取缓存目录失败: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("删除缓存文件失败: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// path 返回缓存键对应的文件路径
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// CachedSummarizer 为Summarizer加上磁盘缓存：相同的提交记录、渲染后的提示词和AI配置直接返回缓存的摘要
+type CachedSummarizer struct {
+	Summarizer
+	cache    *Cache
+	provider string
+	config   Config
+}
+
+// 确保CachedSummarizer实现了StreamSummarizer和StructuredSummarizer接口
+var (
+	_ StreamSummarizer     = (*CachedSummarizer)(nil)
+	_ StructuredSummarizer = (*CachedSummarizer)(nil)
+)
+
+// NewCachedSummarizer 创建带缓存的Summarizer，cfg是创建底层Summarizer使用的配置，
+// 其中影响摘要内容的模型、地址和分块配置都计入缓存键
+func NewCachedSummarizer(summarizer Summarizer, cache *Cache, provider string, cfg Config) *CachedSummarizer {
+	return &CachedSummarizer{
+		Summarizer: summarizer,
+		cache:      cache,
+		provider:   provider,
+		config:     cfg,
+	}
+}
+
+// SummarizeCommitsWithPrompt 优先返回缓存的摘要，未命中时调用底层的Summarizer并写入缓存
+func (c *CachedSummarizer) SummarizeCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error) {
+	return c.StreamCommitsWithPrompt(ctx, commits, promptType, info, io.Discard)
+}
+
+// StreamCommitsWithPrompt 优先返回缓存的摘要（一次性写入w），
+// 未命中时调用底层的Summarizer（支持时使用流式输出）并写入缓存
+func (c *CachedSummarizer) StreamCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, w io.Writer) (string, error) {
+	if len(commits) == 0 {
+		return c.Summarizer.SummarizeCommitsWithPrompt(ctx, commits, promptType, info)
+	}
+
+	key, err := c.key(commits, promptType, "", info)
+	if err != nil {
+		// 提示词无法渲染时不使用缓存，由底层的Summarizer返回错误
+		return c.summarize(ctx, commits, promptType, info, w)
+	}
+	if summary, ok := c.cache.Get(key); ok {
+		i18n.Println("使用缓存的AI摘要 (使用--no-cache重新生成)")
+		fmt.Fprint(w, summary)
+		return summary, nil
+	}
+
+	summary, err := c.summarize(ctx, commits, promptType, info, w)
+	if err != nil {
+		return "", err
+	}
+
+	c.put(key, commits, promptType, summary)
+	return summary, nil
+}
+
+// summarize 调用底层的Summarizer生成摘要，支持时使用流式输出
+func (c *CachedSummarizer) summarize(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, w io.Writer) (string, error) {
+	if streamSummarizer, ok := c.Summarizer.(StreamSummarizer); ok && w != io.Discard {
+		return streamSummarizer.StreamCommitsWithPrompt(ctx, commits, promptType, info, w)
+	}
+
+	summary, err := c.Summarizer.SummarizeCommitsWithPrompt(ctx, commits, promptType, info)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(w, summary)
+	return summary, nil
+}
+
+// SummarizeCommitsStructured 优先返回缓存的结构化摘要，未命中时调用底层的StructuredSummarizer并写入缓存。
+// 底层的Summarizer不支持结构化输出时返回错误
+func (c *CachedSummarizer) SummarizeCommitsStructured(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (*structured.Summary, error) {
+	structuredSummarizer, ok := c.Summarizer.(StructuredSummarizer)
+	if !ok {
+		return nil, errors.New("当前AI提供方不支持结构化输出")
+	}
+	if len(commits) == 0 {
+		return structuredSummarizer.SummarizeCommitsStructured(ctx, commits, promptType, info)
+	}
+
+	// 结构化摘要和普通摘要使用不同的缓存键
+	key, err := c.key(commits, promptType, "structured", info)
+	if err != nil {
+		// 提示词无法渲染时不使用缓存，由底层的Summarizer返回错误
+		return structuredSummarizer.SummarizeCommitsStructured(ctx, commits, promptType, info)
+	}
+	if cached, ok := c.cache.Get(key); ok {
+		if summary, err := structured.Parse(cached); err == nil {
+			i18n.Println("使用缓存的AI摘要 (使用--no-cache重新生成)")
+			return summary, nil
+		}
+	}
+
+	summary, err := structuredSummarizer.SummarizeCommitsStructured(ctx, commits, promptType, info)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(summary)
+	if err != nil {
+		return nil, fmt.Errorf("序列化结构化摘要失败: %w", err)
+	}
+	c.put(key, commits, promptType, string(content))
+	return summary, nil
+}
+
+// put 写入一条缓存，写入失败只打印警告
+func (c *CachedSummarizer) put(key string, commits []git.CommitInfo, promptType PromptType, summary string) {
+	entry := cacheEntry{
+		Provider:   c.provider,
+		Model:      c.config.ModelName,
+		PromptType: string(promptType),
+		Commits:    len(commits),
+		CreatedAt:  time.Now(),
+		Summary:    summary,
+	}
+	if err := c.cache.put(key, entry); err != nil {
+		// 缓存写入失败不影响报告生成
+		i18n.Printf("警告: 写入AI摘要缓存失败: %v\n", err)
+	}
+}
+
+// Key 计算缓存键：渲染后的提示词、提交哈希、AI配置和提供方的SHA-256
+func (c *CachedSummarizer) Key(commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error) {
+	return c.key(commits, promptType, "", info)
+}
+
+// key 计算缓存键，mode非空时区分不同的输出模式。
+// 提示词模板、报告元信息和提交的日期、时区、角色等都会体现在渲染后的提示词中，因此直接对提示词取哈希；
+// 提交按时间排序后再渲染，使缓存键与提交的输入顺序无关
+func (c *CachedSummarizer) key(commits []git.CommitInfo, promptType PromptType, mode string, info PromptInfo) (string, error) {
+	// 模板加载失败时底层的Summarizer会打印警告并使用默认提示词，这里静默地做同样的处理
+	template, err := loadPromptTemplate(promptType, info.Language)
+	if err != nil {
+		template = localized(info.Language, defaultPromptTemplate, defaultPromptTemplateEN)
+	}
+	prompt, err := renderPromptTemplate(template, newPromptData(sortCommitsByDate(commits), info))
+	if err != nil {
+		return "", err
+	}
+
+	hashes := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		hashes = append(hashes, commit.Hash)
+	}
+	sort.Strings(hashes)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "provider=%s\nmodel=%s\nbase_url=%s\n", c.provider, c.config.ModelName, c.config.BaseURL)
+	fmt.Fprintf(h, "chunk_strategy=%s\nmax_prompt_tokens=%d\n", c.config.ChunkStrategy, c.config.MaxPromptTokens)
+	if mode != "" {
+		fmt.Fprintf(h, "mode=%s\n", mode)
+	}
+	fmt.Fprintf(h, "prompt=%s\n", prompt)
+	fmt.Fprintf(h, "commits=%s\n", strings.Join(hashes, ","))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
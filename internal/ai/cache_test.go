@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// countingSummarizer 记录调用次数的Summarizer
+type countingSummarizer struct {
+	calls int
+}
+
+func (c *countingSummarizer) SummarizeCommitsWithPrompt(_ context.Context, commits []git.CommitInfo, _ PromptType, _ PromptInfo) (string, error) {
+	c.calls++
+	return "摘要: " + commits[0].Message, nil
+}
+
+func (c *countingSummarizer) Close() {}
+
+// TestCachedSummarizer 测试缓存命中、未命中和清空
+func TestCachedSummarizer(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	backend := &countingSummarizer{}
+	summarizer := NewCachedSummarizer(backend, cache, "gemini", Config{ModelName: "test-model"})
+	commits := testCommits()
+
+	first, err := summarizer.SummarizeCommitsWithPrompt(context.Background(), commits, BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+
+	// 相同的输入命中缓存，流式输出时一次性写入缓存的摘要
+	var streamed strings.Builder
+	second, err := summarizer.StreamCommitsWithPrompt(context.Background(), commits, BasicPrompt, PromptInfo{}, &streamed)
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if backend.calls != 1 || second != first || streamed.String() != first {
+		t.Errorf("第二次应命中缓存, 调用 %d 次, 得到: %q, %q", backend.calls, second, streamed.String())
+	}
+
+	// 提示词模板、模型或提供方不同时不命中缓存
+	if _, err := summarizer.SummarizeCommitsWithPrompt(context.Background(), commits, DetailedPrompt, PromptInfo{}); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	other := NewCachedSummarizer(backend, cache, "openai", Config{ModelName: "test-model"})
+	if _, err := other.SummarizeCommitsWithPrompt(context.Background(), commits, BasicPrompt, PromptInfo{}); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if backend.calls != 3 {
+		t.Errorf("不同的提示词和提供方不应命中缓存, 调用 %d 次", backend.calls)
+	}
+
+	// 清空缓存后重新生成
+	removed, err := cache.Clear()
+	if err != nil || removed != 3 {
+		t.Errorf("应删除3条缓存, 得到: %d (err: %v)", removed, err)
+	}
+	if _, err := summarizer.SummarizeCommitsWithPrompt(context.Background(), commits, BasicPrompt, PromptInfo{}); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if backend.calls != 4 {
+		t.Errorf("清空缓存后应重新生成, 调用 %d 次", backend.calls)
+	}
+}
+
+// cacheKey 计算缓存键，失败时终止测试
+func cacheKey(t *testing.T, summarizer *CachedSummarizer, commits []git.CommitInfo, info PromptInfo) string {
+	t.Helper()
+	key, err := summarizer.Key(commits, BasicPrompt, info)
+	if err != nil {
+		t.Fatalf("计算缓存键失败: %v", err)
+	}
+	return key
+}
+
+// TestCacheKeyOrder 测试缓存键与提交顺序无关
+func TestCacheKeyOrder(t *testing.T) {
+	summarizer := NewCachedSummarizer(&countingSummarizer{}, NewCache(t.TempDir()), "gemini", Config{})
+	commits := []git.CommitInfo{{Hash: "aaa"}, {Hash: "bbb"}}
+	reversed := []git.CommitInfo{{Hash: "bbb"}, {Hash: "aaa"}}
+
+	if cacheKey(t, summarizer, commits, PromptInfo{}) != cacheKey(t, summarizer, reversed, PromptInfo{}) {
+		t.Error("相同的提交集合应该得到相同的缓存键")
+	}
+	if cacheKey(t, summarizer, commits, PromptInfo{}) == cacheKey(t, summarizer, commits[:1], PromptInfo{}) {
+		t.Error("不同的提交集合应该得到不同的缓存键")
+	}
+}
+
+// TestCacheKeyOptions 测试影响摘要内容的配置和提交信息都计入缓存键
+func TestCacheKeyOptions(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	commits := testCommits()
+	base := cacheKey(t, NewCachedSummarizer(&countingSummarizer{}, cache, "openai", Config{}), commits, PromptInfo{})
+
+	// AI配置不同
+	configs := []Config{
+		{BaseURL: "http://localhost:8000/v1"},
+		{ChunkStrategy: ChunkByRepo},
+		{MaxPromptTokens: 1000},
+	}
+	for _, cfg := range configs {
+		if cacheKey(t, NewCachedSummarizer(&countingSummarizer{}, cache, "openai", cfg), commits, PromptInfo{}) == base {
+			t.Errorf("配置 %+v 应该得到不同的缓存键", cfg)
+		}
+	}
+
+	// 提交的日期（--date-field、--tz）、角色和报告语言不同
+	summarizer := NewCachedSummarizer(&countingSummarizer{}, cache, "openai", Config{})
+	shifted := testCommits()
+	shifted[0].Date = shifted[0].Date.In(time.FixedZone("UTC+8", 8*60*60))
+	coAuthored := testCommits()
+	coAuthored[0].Role = git.RoleCoAuthor
+	for name, key := range map[string]string{
+		"时区":   cacheKey(t, summarizer, shifted, PromptInfo{}),
+		"角色":   cacheKey(t, summarizer, coAuthored, PromptInfo{}),
+		"报告语言": cacheKey(t, summarizer, commits, PromptInfo{Language: i18n.English}),
+	} {
+		if key == base {
+			t.Errorf("%s不同时应该得到不同的缓存键", name)
+		}
+	}
+}
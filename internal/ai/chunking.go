@@ -0,0 +1,293 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// ChunkStrategy 表示提示词超出模型上下文时提交记录的分块策略
+type ChunkStrategy string
+
+const (
+	// ChunkAuto 按token估算顺序切分提交记录
+	ChunkAuto ChunkStrategy = "auto"
+	// ChunkByRepo 按仓库分块，单个仓库过大时再按token切分
+	ChunkByRepo ChunkStrategy = "repo"
+	// ChunkByWeek 按自然周分块，单周过大时再按token切分
+	ChunkByWeek ChunkStrategy = "week"
+	// ChunkNone 不分块，始终发送完整的提示词
+	ChunkNone ChunkStrategy = "none"
+)
+
+// ParseChunkStrategy 根据字符串返回对应的分块策略，空字符串表示auto
+func ParseChunkStrategy(strategy string) (ChunkStrategy, error) {
+	switch ChunkStrategy(strings.ToLower(strategy)) {
+	case "", ChunkAuto:
+		return ChunkAuto, nil
+	case ChunkByRepo:
+		return ChunkByRepo, nil
+	case ChunkByWeek:
+		return ChunkByWeek, nil
+	case ChunkNone:
+		return ChunkNone, nil
+	default:
+		return "", fmt.Errorf("未知的分块策略: %s (可选: auto, repo, week, none)", strategy)
+	}
+}
+
+// EstimateTokens 粗略估算文本的token数量：
+// 中日韩文字按每个字符1个token计算，其他字符按每4个字符1个token计算
+func EstimateTokens(text string) int {
+	cjk, others := 0, 0
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			others++
+		}
+	}
+	return cjk + (others+3)/4
+}
+
+// generateFunc 根据提示词生成文本，由各AI提供方实现
+type generateFunc func(ctx context.Context, prompt string) (string, error)
+
+// commitChunk 一段待总结的提交记录
+type commitChunk struct {
+	Label   string // 分段名称，如仓库名或周
+	Commits []git.CommitInfo
+}
+
+// chunkSummary 一段提交记录的摘要
+type chunkSummary struct {
+	Label   string
+	Summary string
+}
+
+// mapPromptTemplate 分段总结时使用的提示词
+const mapPromptTemplate = `你是一位专业的工作报告生成助手。以下是较长时间范围内的一部分Git提交记录（{{.ChunkLabel}}）。
+请用简洁的要点总结这部分提交记录中完成的工作，保留仓库、模块、功能名称等关键信息，不要遗漏重要的改动。这份摘要稍后会与其他部分的摘要合并成完整的报告。
+
+提交记录：
+{{.CommitMessages}}`
+
+// mergePromptTemplate 分段摘要仍然过长时，合并多段摘要使用的提示词
+const mergePromptTemplate = `你是一位专业的工作报告生成助手。请将以下多段工作摘要合并为一段摘要，保留所有关键信息，去除重复内容。
+
+{{.Summaries}}`
+
+// mapPromptTemplateEN 英文的分段总结提示词
+const mapPromptTemplateEN = `You are a professional work report assistant. The following is part of the Git commits over a long time range ({{.ChunkLabel}}).
+Summarize the work completed in these commits as concise bullet points, keeping key details such as repository, module and feature names and not leaving out important changes. This summary will later be merged with the summaries of the other parts into a complete report.
+
+Commits:
+{{.CommitMessages}}`
+
+// mergePromptTemplateEN 英文的合并摘要提示词
+const mergePromptTemplateEN = `You are a professional work report assistant. Merge the following work summaries into a single summary, keeping all key information and removing duplicates.
+
+{{.Summaries}}`
+
+// summarizeCommits 总结提交记录。提示词不超过maxTokens时直接生成；
+// 否则按分块策略将提交记录分段，先分别总结每一段（map），再把分段摘要填入原提示词模板生成最终报告（reduce）。
+// final用于生成最终结果（如流式输出），为nil时使用generate
+func summarizeCommits(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, strategy ChunkStrategy, maxTokens int, generate, final generateFunc) (string, error) {
+	if final == nil {
+		final = generate
+	}
+
+	template := loadPromptTemplateOrDefault(promptType, info.Language)
+	prompt, err := renderPromptTemplate(template, newPromptData(commits, info))
+	if err != nil {
+		return "", err
+	}
+	promptTokens := EstimateTokens(prompt)
+	chunks := planChunks(commits, promptTokens, strategy, maxTokens, info.Language)
+	if chunks == nil {
+		return final(ctx, prompt)
+	}
+
+	// map: 分段总结
+	i18n.Printf("提示词约 %d tokens，超过上限 %d，分为 %d 段分别总结\n", promptTokens, maxTokens, len(chunks))
+
+	summaries := make([]chunkSummary, 0, len(chunks))
+	for i, chunk := range chunks {
+		i18n.Printf("  正在总结第 %d/%d 段: %s (%d 条提交)\n", i+1, len(chunks), chunk.Label, len(chunk.Commits))
+
+		data := newPromptData(chunk.Commits, info)
+		data.ChunkLabel = chunk.Label
+		chunkPrompt, err := renderPromptTemplate(localized(info.Language, mapPromptTemplate, mapPromptTemplateEN), data)
+		if err != nil {
+			return "", err
+		}
+		summary, err := generate(ctx, chunkPrompt)
+		if err != nil {
+			return "", fmt.Errorf("总结第 %d 段提交记录失败: %w", i+1, err)
+		}
+		summaries = append(summaries, chunkSummary{Label: chunk.Label, Summary: summary})
+	}
+
+	// reduce: 合并分段摘要
+	return reduceSummaries(ctx, summaries, template, newPromptData(commits, info), maxTokens, generate, final)
+}
+
+// planChunks 提示词超出maxTokens时按分块策略将提交记录分段，不需要分段时返回nil。
+// 每段的预算扣除lang语言的分段总结提示词，分段名称也使用该语言
+func planChunks(commits []git.CommitInfo, promptTokens int, strategy ChunkStrategy, maxTokens int, lang i18n.Language) []commitChunk {
+	if strategy == ChunkNone || maxTokens <= 0 || promptTokens <= maxTokens {
+		return nil
+	}
+	mapTemplate := localized(lang, mapPromptTemplate, mapPromptTemplateEN)
+	return chunkCommits(commits, strategy, maxTokens-EstimateTokens(mapTemplate), lang)
+}
+
+// reduceSummaries 将分段摘要代替提交记录文本填入提示词模板生成最终报告，
+// 如果合并后仍然超出上限，先两两合并分段摘要直到可以放入一个提示词。
+// 此时.Commits为空，.CommitCount、.Repos等统计仍然是全部提交的数据
+func reduceSummaries(ctx context.Context, summaries []chunkSummary, template string, data PromptData, maxTokens int, generate, final generateFunc) (string, error) {
+	// 分段摘要代替了提交记录，清空Commits，避免模板通过{{range .Commits}}重新展开全部提交
+	data.Commits = nil
+	defaultTemplate := localized(data.Language, defaultPromptTemplate, defaultPromptTemplateEN)
+	for {
+		intro := fmt.Sprintf(localized(data.Language,
+			"（提交记录较多，共 %d 条，已分 %d 段预先总结，以下为各段的摘要）\n\n",
+			"(There are many commits, %d in total, pre-summarized in %d chunks. The chunk summaries follow.)\n\n"),
+			data.CommitCount, len(summaries))
+		data.CommitMessages = intro + joinSummaries(summaries)
+		prompt, err := renderPromptTemplate(template, data)
+		if err != nil {
+			return "", err
+		}
+		// 模板没有引用.CommitMessages时分段摘要无法进入提示词，改用默认提示词生成最终报告
+		if !strings.Contains(prompt, data.CommitMessages) && template != defaultTemplate {
+			i18n.Println("警告: 提示词模板没有引用{{.CommitMessages}}，无法填入分段摘要，使用默认提示词生成最终报告")
+			template = defaultTemplate
+			continue
+		}
+		if EstimateTokens(prompt) <= maxTokens || len(summaries) <= 1 {
+			return final(ctx, prompt)
+		}
+
+		i18n.Printf("  分段摘要仍然过长，合并 %d 段摘要\n", len(summaries))
+		merged := make([]chunkSummary, 0, (len(summaries)+1)/2)
+		for i := 0; i < len(summaries); i += 2 {
+			if i+1 == len(summaries) {
+				merged = append(merged, summaries[i])
+				continue
+			}
+
+			pair := summaries[i : i+2]
+			mergePrompt := strings.ReplaceAll(localized(data.Language, mergePromptTemplate, mergePromptTemplateEN), "{{.Summaries}}", joinSummaries(pair))
+			summary, err := generate(ctx, mergePrompt)
+			if err != nil {
+				return "", fmt.Errorf("合并分段摘要失败: %w", err)
+			}
+			merged = append(merged, chunkSummary{
+				Label:   pair[0].Label + " ~ " + pair[1].Label,
+				Summary: summary,
+			})
+		}
+		summaries = merged
+	}
+}
+
+// joinSummaries 将多段摘要拼接为带标题的文本
+func joinSummaries(summaries []chunkSummary) string {
+	var result strings.Builder
+	for _, s := range summaries {
+		fmt.Fprintf(&result, "### %s\n%s\n\n", s.Label, strings.TrimSpace(s.Summary))
+	}
+	return result.String()
+}
+
+// chunkCommits 按分块策略对提交记录分组，每组再按token预算切分。
+// 分段名称会填入分段总结的提示词和最终报告的提示词，因此使用报告语言lang
+func chunkCommits(commits []git.CommitInfo, strategy ChunkStrategy, budget int, lang i18n.Language) []commitChunk {
+	t := func(text string) string { return i18n.Translate(lang, text) }
+
+	var groups []commitChunk
+	switch strategy {
+	case ChunkByRepo:
+		groups = groupCommits(commits, func(commit git.CommitInfo) string {
+			if commit.RepoPath == "" {
+				return t("当前仓库")
+			}
+			return fmt.Sprintf(t("仓库 %s"), commit.RepoPath)
+		})
+	case ChunkByWeek:
+		groups = groupCommits(commits, func(commit git.CommitInfo) string {
+			year, week := commit.Date.ISOWeek()
+			return fmt.Sprintf(t("%d年第%02d周"), year, week)
+		})
+	default:
+		groups = []commitChunk{{Label: t("提交记录"), Commits: commits}}
+	}
+
+	var chunks []commitChunk
+	for _, group := range groups {
+		parts := splitByTokens(group.Commits, budget)
+		for i, part := range parts {
+			label := group.Label
+			if len(parts) > 1 {
+				label = fmt.Sprintf(t("%s 第 %d/%d 部分"), group.Label, i+1, len(parts))
+			}
+			chunks = append(chunks, commitChunk{Label: label, Commits: part})
+		}
+	}
+
+	return chunks
+}
+
+// groupCommits 按key对提交记录分组，分组按key排序，组内保持原有顺序
+func groupCommits(commits []git.CommitInfo, key func(git.CommitInfo) string) []commitChunk {
+	grouped := make(map[string][]git.CommitInfo)
+	for _, commit := range commits {
+		k := key(commit)
+		grouped[k] = append(grouped[k], commit)
+	}
+
+	keys := make([]string, 0, len(grouped))
+	for k := range grouped {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	groups := make([]commitChunk, 0, len(keys))
+	for _, k := range keys {
+		groups = append(groups, commitChunk{Label: k, Commits: grouped[k]})
+	}
+	return groups
+}
+
+// splitByTokens 按token预算顺序切分提交记录，单个提交超出预算时独占一段
+func splitByTokens(commits []git.CommitInfo, budget int) [][]git.CommitInfo {
+	var parts [][]git.CommitInfo
+	var current []git.CommitInfo
+	currentTokens := 0
+
+	for _, commit := range commits {
+		var text strings.Builder
+		// 只用于估算token，中文标签的估算值偏大，结果更保守
+		formatCommit(&text, len(current)+1, commit, i18n.Chinese)
+		tokens := EstimateTokens(text.String())
+
+		if len(current) > 0 && currentTokens+tokens > budget {
+			parts = append(parts, current)
+			current = nil
+			currentTokens = 0
+		}
+		current = append(current, commit)
+		currentTokens += tokens
+	}
+	if len(current) > 0 {
+		parts = append(parts, current)
+	}
+
+	return parts
+}
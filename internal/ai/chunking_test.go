@@ -0,0 +1,208 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// TestEstimateTokens 测试token估算
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"添加周报", 4},
+		{"feat: 添加周报", 6}, // "feat: " 6个字符 = 2, 中文4个字 = 4
+	}
+
+	for _, test := range tests {
+		if got := EstimateTokens(test.text); got != test.expected {
+			t.Errorf("EstimateTokens(%q): 期望 %d, 得到 %d", test.text, test.expected, got)
+		}
+	}
+}
+
+// TestParseChunkStrategy 测试分块策略解析
+func TestParseChunkStrategy(t *testing.T) {
+	if strategy, err := ParseChunkStrategy(""); err != nil || strategy != ChunkAuto {
+		t.Errorf("空字符串应解析为auto, 得到: %s (err: %v)", strategy, err)
+	}
+	if strategy, err := ParseChunkStrategy("Week"); err != nil || strategy != ChunkByWeek {
+		t.Errorf("应解析为week, 得到: %s (err: %v)", strategy, err)
+	}
+	if _, err := ParseChunkStrategy("daily"); err == nil {
+		t.Error("未知的分块策略应该返回错误")
+	}
+}
+
+// manyCommits 生成指定数量的测试提交，平均分布在两个仓库和两周内
+func manyCommits(n int) []git.CommitInfo {
+	start := time.Date(2025, 5, 12, 10, 0, 0, 0, time.UTC) // 周一
+	commits := make([]git.CommitInfo, 0, n)
+	for i := 0; i < n; i++ {
+		commits = append(commits, git.CommitInfo{
+			Hash:     fmt.Sprintf("%016d", i),
+			Author:   "John Doe",
+			Date:     start.Add(time.Duration(i) * 14 * 24 * time.Hour / time.Duration(n)),
+			Message:  fmt.Sprintf("feat: 第%d个功能", i),
+			RepoPath: []string{"backend", "frontend"}[i%2],
+		})
+	}
+	return commits
+}
+
+// TestChunkCommits 测试按仓库和按周分块
+func TestChunkCommits(t *testing.T) {
+	commits := manyCommits(8)
+
+	byRepo := chunkCommits(commits, ChunkByRepo, 1<<20, i18n.Chinese)
+	if len(byRepo) != 2 || byRepo[0].Label != "仓库 backend" || len(byRepo[0].Commits) != 4 {
+		t.Errorf("应按仓库分为2段, 得到: %+v", byRepo)
+	}
+
+	byWeek := chunkCommits(commits, ChunkByWeek, 1<<20, i18n.Chinese)
+	if len(byWeek) != 2 || byWeek[0].Label != "2025年第20周" || byWeek[1].Label != "2025年第21周" {
+		t.Errorf("应按周分为2段, 得到: %+v", byWeek)
+	}
+
+	// 预算很小时每个提交单独一段
+	auto := chunkCommits(commits, ChunkAuto, 1, i18n.Chinese)
+	if len(auto) != len(commits) {
+		t.Errorf("应分为 %d 段, 得到: %d", len(commits), len(auto))
+	}
+	if auto[0].Label != "提交记录 第 1/8 部分" {
+		t.Errorf("分段名称不正确: %s", auto[0].Label)
+	}
+}
+
+// TestSummarizeCommitsEnglishChunks 测试英文报告分段总结时，分段名称和提示词中不包含中文
+func TestSummarizeCommitsEnglishChunks(t *testing.T) {
+	commits := manyCommits(40)
+	for i := range commits {
+		commits[i].Message = fmt.Sprintf("feat: feature %d", i)
+		commits[i].Author = "John Doe"
+	}
+	info := PromptInfo{Language: i18n.English}
+
+	for _, strategy := range []ChunkStrategy{ChunkAuto, ChunkByRepo, ChunkByWeek} {
+		var prompts []string
+		generate := func(_ context.Context, prompt string) (string, error) {
+			prompts = append(prompts, prompt)
+			return fmt.Sprintf("summary %d", len(prompts)), nil
+		}
+		if _, err := summarizeCommits(context.Background(), commits, BasicPrompt, info, strategy, 300, generate, nil); err != nil {
+			t.Fatalf("%s: 生成摘要失败: %v", strategy, err)
+		}
+		if len(prompts) < 3 {
+			t.Fatalf("%s: 应分段总结, 只调用了 %d 次", strategy, len(prompts))
+		}
+		for _, prompt := range prompts {
+			if strings.ContainsFunc(prompt, func(r rune) bool { return unicode.Is(unicode.Han, r) }) {
+				t.Errorf("%s: 英文提示词不应包含中文:\n%s", strategy, prompt)
+				break
+			}
+		}
+	}
+
+	if chunks := chunkCommits(commits, ChunkByWeek, 1<<20, i18n.English); chunks[0].Label != "2025 week 20" {
+		t.Errorf("英文的分段名称不正确: %s", chunks[0].Label)
+	}
+}
+
+// TestSummarizeCommitsMapReduce 测试超出上限时的分段总结和合并
+func TestSummarizeCommitsMapReduce(t *testing.T) {
+	commits := manyCommits(40)
+
+	var prompts []string
+	generate := func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return fmt.Sprintf("摘要%d", len(prompts)), nil
+	}
+
+	// 上限足够大时只调用一次
+	if _, err := summarizeCommits(context.Background(), commits, BasicPrompt, PromptInfo{}, ChunkAuto, 1<<20, generate, nil); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if len(prompts) != 1 {
+		t.Fatalf("上限足够大时应只调用一次, 得到: %d", len(prompts))
+	}
+
+	// 按仓库分块：两个仓库各一段，再加一次合并
+	prompts = nil
+	summary, err := summarizeCommits(context.Background(), commits, BasicPrompt, PromptInfo{}, ChunkByRepo, 1200, generate, nil)
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if len(prompts) != 3 {
+		t.Fatalf("应调用3次 (2段 + 合并), 得到: %d", len(prompts))
+	}
+	if !strings.Contains(prompts[0], "仓库 backend") || !strings.Contains(prompts[1], "仓库 frontend") {
+		t.Error("分段提示词应包含仓库名称")
+	}
+	reducePrompt := prompts[2]
+	if !strings.Contains(reducePrompt, "### 仓库 backend\n摘要1") || !strings.Contains(reducePrompt, "### 仓库 frontend\n摘要2") {
+		t.Errorf("合并提示词应包含各段摘要, 得到:\n%s", reducePrompt)
+	}
+	if summary != "摘要3" {
+		t.Errorf("最终摘要应为合并步骤的结果, 得到: %s", summary)
+	}
+
+	// 不分块时始终只调用一次
+	prompts = nil
+	if _, err := summarizeCommits(context.Background(), commits, BasicPrompt, PromptInfo{}, ChunkNone, 10, generate, nil); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if len(prompts) != 1 {
+		t.Errorf("不分块时应只调用一次, 得到: %d", len(prompts))
+	}
+}
+
+// TestSummarizeCommitsReduceCommitsTemplate 测试通过{{range .Commits}}引用提交的模板在合并步骤不会重新展开全部提交
+func TestSummarizeCommitsReduceCommitsTemplate(t *testing.T) {
+	commits := manyCommits(40)
+
+	var prompts []string
+	generate := func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return fmt.Sprintf("摘要%d", len(prompts)), nil
+	}
+
+	// 同时引用.Commits和.CommitMessages：合并步骤中.Commits为空，.CommitCount仍为全部提交数
+	path := filepath.Join(t.TempDir(), "commits.md")
+	content := "共{{.CommitCount}}条提交\n{{range .Commits}}- {{.Message}}\n{{end}}{{.CommitMessages}}"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("写入提示词文件失败: %v", err)
+	}
+	if _, err := summarizeCommits(context.Background(), commits, PromptType(path), PromptInfo{}, ChunkByRepo, 1200, generate, nil); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	reducePrompt := prompts[len(prompts)-1]
+	if strings.Contains(reducePrompt, "feat: 第0个功能") || !strings.HasPrefix(reducePrompt, "共40条提交\n") || !strings.Contains(reducePrompt, "### 仓库 backend\n摘要1") {
+		t.Errorf("合并提示词应只包含分段摘要, 得到:\n%s", reducePrompt)
+	}
+
+	// 只引用.Commits时分段摘要无法填入模板，改用默认提示词
+	prompts = nil
+	if err := os.WriteFile(path, []byte("{{range .Commits}}- {{.Message}}\n{{end}}"), 0o644); err != nil {
+		t.Fatalf("写入提示词文件失败: %v", err)
+	}
+	if _, err := summarizeCommits(context.Background(), commits, PromptType(path), PromptInfo{}, ChunkByRepo, 200, generate, nil); err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	reducePrompt = prompts[len(prompts)-1]
+	if strings.Contains(reducePrompt, "feat: 第0个功能") || !strings.Contains(reducePrompt, "已分") || !strings.Contains(reducePrompt, "你是一位专业的工作报告生成助手") {
+		t.Errorf("应使用默认提示词填入分段摘要, 得到:\n%s", reducePrompt)
+	}
+}
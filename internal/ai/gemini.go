@@ -2,23 +2,60 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/internal/structured"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
 // 默认模型名称
 const DefaultModelName = "gemini-2.5-flash-preview-05-20"
 
+// DefaultGeminiMaxPromptTokens Gemini单个提示词默认的token上限，低于模型的上下文窗口并留有余量
+const DefaultGeminiMaxPromptTokens = 500000
+
 // GeminiClient 是Gemini AI API的客户端
 type GeminiClient struct {
-	client *genai.Client
-	model  *genai.GenerativeModel
+	client          *genai.Client
+	model           *genai.GenerativeModel
+	jsonModel       *genai.GenerativeModel // 以JSON模式输出结构化摘要的模型
+	chunkStrategy   ChunkStrategy
+	maxPromptTokens int
+	retry           RetryPolicy
+}
+
+// 确保GeminiClient实现了StreamSummarizer和StructuredSummarizer接口
+var (
+	_ StreamSummarizer     = (*GeminiClient)(nil)
+	_ StructuredSummarizer = (*GeminiClient)(nil)
+)
+
+// structuredSummarySchema structured.Summary对应的Gemini响应schema
+var structuredSummarySchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"overview":     {Type: genai.TypeString, Description: "总体工作概述"},
+		"achievements": {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}, Description: "完成的主要工作或成就"},
+		"in_progress":  {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}, Description: "进行中的工作"},
+		"risks":        {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}, Description: "风险、问题或需要关注的事项"},
+		"next_steps":   {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}, Description: "下一步计划"},
+	},
+	Required: []string{"overview", "achievements", "in_progress", "risks", "next_steps"},
+}
+
+func init() {
+	RegisterProvider(ProviderGemini, func(cfg Config) (Summarizer, error) {
+		return NewGeminiClientWithConfig(cfg)
+	})
 }
 
 // NewGeminiClient 创建一个新的Gemini客户端
@@ -28,6 +65,12 @@ func NewGeminiClient() (*GeminiClient, error) {
 
 // NewGeminiClientWithModel 使用指定模型创建一个新的Gemini客户端
 func NewGeminiClientWithModel(modelName string) (*GeminiClient, error) {
+	return NewGeminiClientWithConfig(Config{ModelName: modelName})
+}
+
+// NewGeminiClientWithConfig 根据配置创建一个新的Gemini客户端
+func NewGeminiClientWithConfig(cfg Config) (*GeminiClient, error) {
+	modelName := cfg.ModelName
 	// 如果没有指定模型名称，使用默认模型
 	if modelName == "" {
 		modelName = DefaultModelName
@@ -48,46 +91,62 @@ func NewGeminiClientWithModel(modelName string) (*GeminiClient, error) {
 	// 使用指定的模型
 	model := client.GenerativeModel(modelName)
 
+	// 结构化摘要使用同一模型的JSON模式，并通过响应schema约束输出格式
+	jsonModel := client.GenerativeModel(modelName)
+	jsonModel.ResponseMIMEType = "application/json"
+	jsonModel.ResponseSchema = structuredSummarySchema
+
 	return &GeminiClient{
-		client: client,
-		model:  model,
+		client:          client,
+		model:           model,
+		jsonModel:       jsonModel,
+		chunkStrategy:   cfg.ChunkStrategy,
+		maxPromptTokens: cfg.maxPromptTokensOrDefault(DefaultGeminiMaxPromptTokens),
+		retry:           cfg.retryPolicy(DefaultTimeout),
 	}, nil
 }
 
 // SummarizeCommits 使用AI总结提交记录
-func (g *GeminiClient) SummarizeCommits(commits []git.CommitInfo) (string, error) {
-	return g.SummarizeCommitsWithPrompt(commits, BasicPrompt)
+func (g *GeminiClient) SummarizeCommits(ctx context.Context, commits []git.CommitInfo) (string, error) {
+	return g.SummarizeCommitsWithPrompt(ctx, commits, BasicPrompt, PromptInfo{})
 }
 
 // SummarizeCommitsWithPrompt 使用指定的提示词类型总结提交记录
-func (g *GeminiClient) SummarizeCommitsWithPrompt(commits []git.CommitInfo, promptType PromptType) (string, error) {
+func (g *GeminiClient) SummarizeCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error) {
 	if len(commits) == 0 {
-		return "没有找到提交记录。", nil
+		return noCommitsSummary, nil
 	}
 
-	// 获取时间范围
-	var earliestDate, latestDate time.Time
-	if len(commits) > 0 {
-		earliestDate = commits[len(commits)-1].Date
-		latestDate = commits[0].Date
+	return summarizeCommits(ctx, commits, promptType, info, g.chunkStrategy, g.maxPromptTokens, g.retry.wrap(g.generate), nil)
+}
 
-		// 遍历所有提交，找出最早和最晚的日期
-		for _, commit := range commits {
-			if commit.Date.Before(earliestDate) {
-				earliestDate = commit.Date
-			}
-			if commit.Date.After(latestDate) {
-				latestDate = commit.Date
-			}
-		}
+// StreamCommitsWithPrompt 使用指定的提示词类型总结提交记录，并将最终结果实时写入w
+func (g *GeminiClient) StreamCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, w io.Writer) (string, error) {
+	if len(commits) == 0 {
+		fmt.Fprint(w, noCommitsSummary)
+		return noCommitsSummary, nil
 	}
 
-	// 构建提示词
-	prompt := buildPromptWithTemplate(commits, earliestDate, latestDate, promptType)
+	stream := g.retry.wrapStream(g.generateStream, w)
+	return summarizeCommits(ctx, commits, promptType, info, g.chunkStrategy, g.maxPromptTokens, g.retry.wrap(g.generate), stream)
+}
 
-	// 调用Gemini API
-	ctx := context.Background()
-	resp, err := g.model.GenerateContent(ctx, genai.Text(prompt))
+// SummarizeCommitsStructured 使用JSON模式和响应schema生成结构化的摘要
+func (g *GeminiClient) SummarizeCommitsStructured(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (*structured.Summary, error) {
+	generateJSON := func(ctx context.Context, prompt string) (string, error) {
+		return g.generateWith(ctx, g.jsonModel, prompt)
+	}
+	return summarizeStructured(ctx, commits, promptType, info, g.chunkStrategy, g.maxPromptTokens, g.retry.wrap(g.generate), g.retry.wrap(generateJSON))
+}
+
+// generate 调用Gemini API生成回复
+func (g *GeminiClient) generate(ctx context.Context, prompt string) (string, error) {
+	return g.generateWith(ctx, g.model, prompt)
+}
+
+// generateWith 使用指定的模型配置调用Gemini API生成回复
+func (g *GeminiClient) generateWith(ctx context.Context, model *genai.GenerativeModel, prompt string) (string, error) {
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
 		return "", fmt.Errorf("调用Gemini API失败: %w", err)
 	}
@@ -103,67 +162,139 @@ func (g *GeminiClient) SummarizeCommitsWithPrompt(commits []git.CommitInfo, prom
 	return result.String(), nil
 }
 
+// generateStream 以流式方式调用Gemini API，将收到的内容实时写入w并返回完整的回复
+func (g *GeminiClient) generateStream(ctx context.Context, prompt string, w io.Writer) (string, error) {
+	iter := g.model.GenerateContentStream(ctx, genai.Text(prompt))
+
+	var result strings.Builder
+	for {
+		resp, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("调用Gemini API失败: %w", err)
+		}
+
+		for _, candidate := range resp.Candidates {
+			if candidate.Content == nil {
+				continue
+			}
+			for _, part := range candidate.Content.Parts {
+				text := fmt.Sprintf("%v", part)
+				result.WriteString(text)
+				fmt.Fprint(w, text)
+			}
+		}
+	}
+
+	return result.String(), nil
+}
+
 // buildPromptWithTemplate 使用指定的提示词模板构建提示词
-func buildPromptWithTemplate(commits []git.CommitInfo, _ /*fromDate*/, _ /*toDate*/ time.Time, promptType PromptType) string {
+func buildPromptWithTemplate(commits []git.CommitInfo, info PromptInfo, promptType PromptType) (string, error) {
 	// 获取提示词模板
-	template, err := loadPromptTemplate(promptType)
+	template := loadPromptTemplateOrDefault(promptType, info.Language)
+
+	// 渲染模板中的变量
+	return renderPromptTemplate(template, newPromptData(commits, info))
+}
+
+// loadPromptTemplateOrDefault 加载指定语言的提示词模板，失败时使用默认的提示词
+func loadPromptTemplateOrDefault(promptType PromptType, lang i18n.Language) string {
+	template, err := loadPromptTemplate(promptType, lang)
 	if err != nil {
 		// 如果加载模板失败，使用默认的提示词
-		fmt.Printf("警告: 加载提示词模板失败: %v, 使用默认提示词\n", err)
-		template = defaultPromptTemplate
+		i18n.Printf("警告: 加载提示词模板失败: %v, 使用默认提示词\n", err)
+		template = localized(lang, defaultPromptTemplate, defaultPromptTemplateEN)
 	}
+	return template
+}
 
-	// 构建提交记录字符串
+// formatCommitMessages 使用指定语言的标签构建提交记录字符串
+func formatCommitMessages(commits []git.CommitInfo, lang i18n.Language) string {
 	var commitMessages strings.Builder
-
 	for i, commit := range commits {
-		// 添加提交记录
-		fmt.Fprintf(&commitMessages, "提交 %d:\n", i+1)
-		fmt.Fprintf(&commitMessages, "- 哈希值: %s\n", commit.Hash[:8])
-		fmt.Fprintf(&commitMessages, "- 作者: %s\n", commit.Author)
-		fmt.Fprintf(&commitMessages, "- 日期: %s\n", commit.Date.Format("2006-01-02 15:04:05"))
-
-		// 添加分支信息
-		if len(commit.Branches) > 0 {
-			fmt.Fprintf(&commitMessages, "- 分支: %s\n", strings.Join(commit.Branches, ", "))
+		formatCommit(&commitMessages, i+1, commit, lang)
+	}
+	return commitMessages.String()
+}
+
+// formatCommit 将单个提交格式化为提示词中的一段文本
+func formatCommit(commitMessages *strings.Builder, index int, commit git.CommitInfo, lang i18n.Language) {
+	t := func(text string) string { return i18n.Translate(lang, text) }
+
+	// 添加提交记录
+	fmt.Fprintf(commitMessages, t("提交 %d:\n"), index)
+	fmt.Fprintf(commitMessages, t("- 哈希值: %s\n"), shortHash(commit.Hash))
+	fmt.Fprintf(commitMessages, t("- 作者: %s\n"), commit.Author)
+
+	// 添加共同作者，作为共同作者参与的提交标出角色，便于AI区分结对完成的工作
+	if len(commit.CoAuthors) > 0 {
+		names := make([]string, 0, len(commit.CoAuthors))
+		for _, coAuthor := range commit.CoAuthors {
+			names = append(names, coAuthor.Name)
 		}
+		fmt.Fprintf(commitMessages, t("- 共同作者: %s\n"), strings.Join(names, ", "))
+	}
+	if commit.Role == git.RoleCoAuthor {
+		fmt.Fprintf(commitMessages, t("- 角色: %s\n"), t("共同作者"))
+	}
+	fmt.Fprintf(commitMessages, t("- 日期: %s\n"), commit.Date.Format("2006-01-02 15:04:05"))
 
-		// 添加提交消息
-		fmt.Fprintf(&commitMessages, "- 消息: %s\n", commit.Message)
+	// 添加分支信息
+	if len(commit.Branches) > 0 {
+		fmt.Fprintf(commitMessages, t("- 分支: %s\n"), strings.Join(commit.Branches, ", "))
+	}
 
-		// 添加变更文件
-		if len(commit.ChangedFiles) > 0 {
-			fmt.Fprintf(&commitMessages, "- 变更文件:\n")
-			// 最多显示10个文件
-			maxFiles := 10
-			if len(commit.ChangedFiles) < maxFiles {
-				maxFiles = len(commit.ChangedFiles)
-			}
-			for j := 0; j < maxFiles; j++ {
-				fmt.Fprintf(&commitMessages, "  * %s\n", commit.ChangedFiles[j])
-			}
-			if len(commit.ChangedFiles) > maxFiles {
-				fmt.Fprintf(&commitMessages, "  * ... 以及其他 %d 个文件\n", len(commit.ChangedFiles)-maxFiles)
-			}
+	// 添加提交消息
+	fmt.Fprintf(commitMessages, t("- 消息: %s\n"), commit.Message)
+
+	// 添加提交正文，每行缩进以便和其他字段区分
+	if commit.Body != "" {
+		fmt.Fprint(commitMessages, t("- 说明:\n"))
+		for _, line := range strings.Split(commit.Body, "\n") {
+			fmt.Fprintf(commitMessages, "  %s\n", line)
 		}
+	}
 
-		// 添加空行分隔不同提交
-		fmt.Fprintf(&commitMessages, "\n")
+	// 添加变更的行数
+	if commit.Insertions > 0 || commit.Deletions > 0 {
+		fmt.Fprintf(commitMessages, t("- 变更行数: +%d -%d\n"), commit.Insertions, commit.Deletions)
 	}
 
-	// 替换模板中的变量
-	prompt := strings.ReplaceAll(template, "{{.CommitMessages}}", commitMessages.String())
+	// 添加变更文件
+	if len(commit.ChangedFiles) > 0 {
+		fmt.Fprint(commitMessages, t("- 变更文件:\n"))
+		// 最多显示10个文件
+		maxFiles := 10
+		if len(commit.ChangedFiles) < maxFiles {
+			maxFiles = len(commit.ChangedFiles)
+		}
+		for j := 0; j < maxFiles; j++ {
+			// 重命名的文件同时显示原路径
+			if j < len(commit.FileStats) && commit.FileStats[j].OldPath != "" {
+				fmt.Fprintf(commitMessages, "  * %s -> %s\n", commit.FileStats[j].OldPath, commit.ChangedFiles[j])
+				continue
+			}
+			fmt.Fprintf(commitMessages, "  * %s\n", commit.ChangedFiles[j])
+		}
+		if len(commit.ChangedFiles) > maxFiles {
+			fmt.Fprintf(commitMessages, t("  * ... 以及其他 %d 个文件\n"), len(commit.ChangedFiles)-maxFiles)
+		}
+	}
 
-	return prompt
+	// 添加空行分隔不同提交
+	fmt.Fprintf(commitMessages, "\n")
 }
 
 // GenerateReport 根据提交记录和时间范围生成报告
-func (g *GeminiClient) GenerateReport(commits []git.CommitInfo, fromDate, toDate time.Time) (string, error) {
-	return g.GenerateReportWithPrompt(commits, fromDate, toDate, BasicPrompt)
+func (g *GeminiClient) GenerateReport(ctx context.Context, commits []git.CommitInfo, fromDate, toDate time.Time) (string, error) {
+	return g.GenerateReportWithPrompt(ctx, commits, fromDate, toDate, BasicPrompt)
 }
 
 // GenerateReportWithPrompt 使用指定的提示词类型生成报告
-func (g *GeminiClient) GenerateReportWithPrompt(commits []git.CommitInfo, fromDate, toDate time.Time, promptType PromptType) (string, error) {
+func (g *GeminiClient) GenerateReportWithPrompt(ctx context.Context, commits []git.CommitInfo, fromDate, toDate time.Time, promptType PromptType) (string, error) {
 	// 这个方法实际上是对SummarizeCommits的封装，提供更明确的接口
 	if len(commits) == 0 {
 		// 根据时间范围返回不同的消息
@@ -187,24 +318,13 @@ func (g *GeminiClient) GenerateReportWithPrompt(commits []git.CommitInfo, fromDa
 	}
 
 	// 构建提示词
-	prompt := buildPromptWithTemplate(commits, fromDate, toDate, promptType)
-
-	// 调用Gemini API
-	ctx := context.Background()
-	resp, err := g.model.GenerateContent(ctx, genai.Text(prompt))
+	prompt, err := buildPromptWithTemplate(commits, PromptInfo{From: fromDate, To: toDate}, promptType)
 	if err != nil {
-		return "", fmt.Errorf("调用Gemini API失败: %w", err)
+		return "", err
 	}
 
-	// 提取回复
-	var result strings.Builder
-	for _, candidate := range resp.Candidates {
-		for _, part := range candidate.Content.Parts {
-			result.WriteString(fmt.Sprintf("%v", part))
-		}
-	}
-
-	return result.String(), nil
+	// 调用Gemini API
+	return g.retry.wrap(g.generate)(ctx, prompt)
 }
 
 // Close 关闭Gemini客户端
@@ -227,69 +347,15 @@ const defaultPromptTemplate = `你是一位专业的工作报告生成助手。
 
 保持简洁明了，重点突出实际完成的工作。`
 
-// loadPromptTemplate 从文件加载提示词模板
-func loadPromptTemplate(promptType PromptType) (string, error) {
-	// 检查是否为自定义提示词
-	if IsCustomPrompt(promptType) {
-		// 自定义提示词，从文件加载
-		customPrompt, err := LoadCustomPrompt(string(promptType))
-		if err != nil {
-			return "", fmt.Errorf("加载自定义提示词失败: %w", err)
-		}
-
-		// 确保自定义提示词包含占位符
-		if !strings.Contains(customPrompt, "{{.CommitMessages}}") {
-			// 如果没有占位符，在末尾添加
-			customPrompt += "\n\n提交记录：\n{{.CommitMessages}}"
-		}
-
-		return customPrompt, nil
-	}
-
-	// 预设提示词，根据提示词类型确定文件名
-	var filename string
-	switch promptType {
-	case BasicPrompt:
-		filename = "basic.txt"
-	case DetailedPrompt:
-		filename = "detailed.txt"
-	case TargetedPrompt:
-		filename = "targeted.txt"
-	default:
-		filename = "basic.txt"
-	}
+// 英文的默认提示词模板
+const defaultPromptTemplateEN = `You are a professional work report assistant. Based on the following Git commits, write a concise work summary in English.
 
-	// 尝试从多个可能的位置加载模板
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("获取当前目录失败: %w", err)
-	}
-
-	// 尝试从多个可能的位置加载模板
-	paths := []string{
-		fmt.Sprintf("%s/prompts/%s", cwd, filename),                                 // 当前目录下的prompts目录
-		fmt.Sprintf("%s/../prompts/%s", cwd, filename),                              // 上级目录下的prompts目录
-		fmt.Sprintf("%s/../../prompts/%s", cwd, filename),                           // 上上级目录下的prompts目录
-		fmt.Sprintf("/Users/cola/code/kway-teow/git-work-log/prompts/%s", filename), // 项目根目录
-	}
-
-	var content []byte
-	var loadErr error
-
-	// 尝试每个路径
-	for _, path := range paths {
-		content, loadErr = loadPromptTemplateFromPath(path)
-		if loadErr == nil {
-			// 成功加载模板
-			return string(content), nil
-		}
-	}
+Commits:
+{{.CommitMessages}}
 
-	// 所有路径都失败了，返回最后一个错误
-	return "", fmt.Errorf("无法加载提示词模板: %w", loadErr)
-}
+Please provide:
+1. A short overall summary of the work (no more than 3 sentences)
+2. 3-5 key achievements or completed tasks
+3. Any noticeable themes or patterns in the work
 
-// loadPromptTemplateFromPath 从指定路径加载提示词模板
-func loadPromptTemplateFromPath(path string) ([]byte, error) {
-	return os.ReadFile(path)
-}
+Keep it concise and focus on the work that was actually completed.`
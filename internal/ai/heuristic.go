@@ -0,0 +1,282 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/internal/structured"
+)
+
+// HeuristicSummarizer 是基于规则的摘要生成器，不调用任何AI服务。
+// 它按仓库、分支和约定式提交类型对提交记录分组，输出确定性的摘要，
+// 适用于CI和无法访问网络的环境
+type HeuristicSummarizer struct{}
+
+// 确保HeuristicSummarizer实现了StructuredSummarizer接口
+var _ StructuredSummarizer = (*HeuristicSummarizer)(nil)
+
+func init() {
+	RegisterProvider(ProviderHeuristic, func(_ Config) (Summarizer, error) {
+		return NewHeuristicSummarizer(), nil
+	})
+}
+
+// NewHeuristicSummarizer 创建一个新的规则摘要生成器
+func NewHeuristicSummarizer() *HeuristicSummarizer {
+	return &HeuristicSummarizer{}
+}
+
+// commitType 约定式提交类型及其显示名称
+type commitType struct {
+	Name  string
+	Label string
+}
+
+// commitTypes 约定式提交类型，按在摘要中的显示顺序排列
+var commitTypes = []commitType{
+	{"feat", "新功能"},
+	{"fix", "问题修复"},
+	{"perf", "性能优化"},
+	{"refactor", "重构"},
+	{"docs", "文档"},
+	{"test", "测试"},
+	{"build", "构建"},
+	{"ci", "持续集成"},
+	{"style", "代码格式"},
+	{"chore", "杂项"},
+	{"revert", "回滚"},
+	{otherCommitType, "其他"},
+}
+
+// otherCommitType 不符合约定式提交规范的提交类型
+const otherCommitType = "other"
+
+// unknownBranch 没有分支信息的提交所属的分组名称
+const unknownBranch = "未标记分支"
+
+// conventionalCommitPattern 匹配约定式提交消息，如 feat(report)!: 添加周报
+var conventionalCommitPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// conventionalCommit 解析后的约定式提交消息
+type conventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// parseConventionalCommit 解析约定式提交消息，不符合规范的消息类型为other
+func parseConventionalCommit(message string) conventionalCommit {
+	// 只解析第一行
+	subject := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+
+	matches := conventionalCommitPattern.FindStringSubmatch(subject)
+	if matches == nil {
+		return conventionalCommit{Type: otherCommitType, Description: subject}
+	}
+
+	typ := strings.ToLower(matches[1])
+	if commitTypeLabel(typ) == "" {
+		// 未知类型按普通提交处理
+		return conventionalCommit{Type: otherCommitType, Description: subject}
+	}
+
+	return conventionalCommit{
+		Type:        typ,
+		Scope:       matches[2],
+		Breaking:    matches[3] == "!",
+		Description: matches[4],
+	}
+}
+
+// commitTypeLabel 返回提交类型的显示名称，未知类型返回空字符串
+func commitTypeLabel(typ string) string {
+	for _, ct := range commitTypes {
+		if ct.Name == typ {
+			return ct.Label
+		}
+	}
+	return ""
+}
+
+// SummarizeCommitsWithPrompt 按规则总结提交记录，提示词类型对规则摘要没有影响，
+// 摘要使用报告元信息指定的语言
+func (h *HeuristicSummarizer) SummarizeCommitsWithPrompt(_ context.Context, commits []git.CommitInfo, _ PromptType, info PromptInfo) (string, error) {
+	lang := info.Language
+	t := func(text string) string { return i18n.Translate(lang, text) }
+	if len(commits) == 0 {
+		return t(noCommitsSummary), nil
+	}
+
+	sorted := sortCommitsByDate(commits)
+
+	// 按 仓库 -> 分支 -> 类型 分组
+	groups := make(map[string]map[string]map[string][]conventionalCommit)
+	typeCounts := make(map[string]int)
+	breakingCount := 0
+	for _, commit := range sorted {
+		parsed := parseConventionalCommit(commit.Message)
+		typeCounts[parsed.Type]++
+		if parsed.Breaking {
+			breakingCount++
+		}
+
+		branch := t(unknownBranch)
+		if len(commit.Branches) > 0 {
+			branch = commit.Branches[0]
+		}
+
+		if groups[commit.RepoPath] == nil {
+			groups[commit.RepoPath] = make(map[string]map[string][]conventionalCommit)
+		}
+		if groups[commit.RepoPath][branch] == nil {
+			groups[commit.RepoPath][branch] = make(map[string][]conventionalCommit)
+		}
+		groups[commit.RepoPath][branch][parsed.Type] = append(groups[commit.RepoPath][branch][parsed.Type], parsed)
+	}
+
+	var result strings.Builder
+
+	// 总体概述
+	fmt.Fprintf(&result, t("共 %d 条提交，涉及 %d 个仓库，时间范围 %s 至 %s。\n\n"),
+		len(sorted), len(groups),
+		sorted[0].Date.Format("2006-01-02"),
+		sorted[len(sorted)-1].Date.Format("2006-01-02"))
+
+	// 按类型统计
+	typeStats := make([]string, 0, len(commitTypes))
+	for _, ct := range commitTypes {
+		if count := typeCounts[ct.Name]; count > 0 {
+			typeStats = append(typeStats, fmt.Sprintf(t("%s %d 条"), t(ct.Label), count))
+		}
+	}
+	fmt.Fprintf(&result, t("按类型统计：%s\n"), strings.Join(typeStats, t("，")))
+	if breakingCount > 0 {
+		fmt.Fprintf(&result, t("包含 %d 条破坏性变更\n"), breakingCount)
+	}
+
+	// 按仓库和分支输出详情
+	for _, repo := range sortedKeys(groups) {
+		repoName := repo
+		if repoName == "" {
+			repoName = t("当前仓库")
+		}
+
+		repoCount := 0
+		for _, types := range groups[repo] {
+			for _, items := range types {
+				repoCount += len(items)
+			}
+		}
+		fmt.Fprintf(&result, t("\n### %s (%d 条提交)\n"), repoName, repoCount)
+
+		for _, branch := range sortedKeys(groups[repo]) {
+			fmt.Fprintf(&result, t("\n#### 分支: %s\n"), branch)
+
+			for _, ct := range commitTypes {
+				items := groups[repo][branch][ct.Name]
+				if len(items) == 0 {
+					continue
+				}
+
+				fmt.Fprintf(&result, "- %s (%d):\n", t(ct.Label), len(items))
+				for _, item := range items {
+					line := item.Description
+					if item.Scope != "" {
+						line = item.Scope + ": " + line
+					}
+					if item.Breaking {
+						line += t(" [破坏性变更]")
+					}
+					fmt.Fprintf(&result, "  - %s\n", line)
+				}
+			}
+		}
+	}
+
+	return result.String(), nil
+}
+
+// SummarizeCommitsStructured 按规则生成结构化摘要：
+// 新功能、问题修复、性能优化和重构归入主要成果，WIP提交归入进行中的工作，
+// 破坏性变更和回滚归入风险与问题，规则摘要不推测下一步计划
+func (h *HeuristicSummarizer) SummarizeCommitsStructured(_ context.Context, commits []git.CommitInfo, _ PromptType, info PromptInfo) (*structured.Summary, error) {
+	lang := info.Language
+	t := func(text string) string { return i18n.Translate(lang, text) }
+	if len(commits) == 0 {
+		summary := &structured.Summary{Overview: t(noCommitsSummary)}
+		return summary, summary.Validate()
+	}
+
+	sorted := sortCommitsByDate(commits)
+	repos := make(map[string]bool)
+	summary := &structured.Summary{}
+	for _, commit := range sorted {
+		repos[commit.RepoPath] = true
+
+		parsed := parseConventionalCommit(commit.Message)
+		line := parsed.Description
+		if parsed.Scope != "" {
+			line = parsed.Scope + ": " + line
+		}
+
+		switch {
+		case isWIPCommit(parsed.Description):
+			summary.InProgress = append(summary.InProgress, line)
+		case parsed.Type == "feat", parsed.Type == "fix", parsed.Type == "perf", parsed.Type == "refactor":
+			summary.Achievements = append(summary.Achievements, fmt.Sprintf("%s: %s", t(commitTypeLabel(parsed.Type)), line))
+		case parsed.Type == "revert":
+			summary.Risks = append(summary.Risks, t("回滚")+": "+line)
+		}
+		if parsed.Breaking {
+			summary.Risks = append(summary.Risks, t("破坏性变更")+": "+line)
+		}
+	}
+
+	summary.Overview = fmt.Sprintf(t("共 %d 条提交，涉及 %d 个仓库，时间范围 %s 至 %s。"),
+		len(sorted), len(repos),
+		sorted[0].Date.Format("2006-01-02"),
+		sorted[len(sorted)-1].Date.Format("2006-01-02"))
+
+	return summary, summary.Validate()
+}
+
+// wipPattern 匹配以完整单词WIP开头的提交，如 "WIP: 报告导出"、"wip 报告导出" 和 "[WIP] 报告导出"，
+// 不匹配 "wipe cache" 这类以wip开头的其他单词
+var wipPattern = regexp.MustCompile(`(?i)^(?:\[wip\]|wip\b)`)
+
+// isWIPCommit 判断提交是否为未完成的工作
+func isWIPCommit(subject string) bool {
+	return wipPattern.MatchString(strings.TrimSpace(subject))
+}
+
+// sortCommitsByDate 返回按时间升序排列的提交副本，时间相同时按哈希排序，保证输出稳定
+func sortCommitsByDate(commits []git.CommitInfo) []git.CommitInfo {
+	sorted := make([]git.CommitInfo, len(commits))
+	copy(sorted, commits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].Date.Before(sorted[j].Date)
+		}
+		return sorted[i].Hash < sorted[j].Hash
+	})
+	return sorted
+}
+
+// Close 规则摘要生成器没有需要释放的资源
+func (h *HeuristicSummarizer) Close() {}
+
+// sortedKeys 返回map按字母排序的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// TestParseConventionalCommit 测试约定式提交消息的解析
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected conventionalCommit
+	}{
+		{"feat(report): 添加周报", conventionalCommit{Type: "feat", Scope: "report", Description: "添加周报"}},
+		{"fix!: 修复关键错误", conventionalCommit{Type: "fix", Breaking: true, Description: "修复关键错误"}},
+		{"Fix: 大写类型", conventionalCommit{Type: "fix", Description: "大写类型"}},
+		{"Update README", conventionalCommit{Type: otherCommitType, Description: "Update README"}},
+		{"wip: 未知类型", conventionalCommit{Type: otherCommitType, Description: "wip: 未知类型"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			result := parseConventionalCommit(test.message)
+			if result != test.expected {
+				t.Errorf("期望 %+v, 得到 %+v", test.expected, result)
+			}
+		})
+	}
+}
+
+// TestIsWIPCommit 测试WIP提交只按完整单词匹配
+func TestIsWIPCommit(t *testing.T) {
+	tests := map[string]bool{
+		"wip":              true,
+		"WIP: 导出PDF":       true,
+		"wip 导出PDF":        true,
+		"[WIP] 导出PDF":      true,
+		"wipe cache":       false,
+		"Wiping old files": false,
+		"fix wip handling": false,
+	}
+
+	for subject, expected := range tests {
+		if result := isWIPCommit(subject); result != expected {
+			t.Errorf("isWIPCommit(%q) 期望 %v, 得到 %v", subject, expected, result)
+		}
+	}
+}
+
+// TestHeuristicSummarizer 测试规则摘要的分组和输出稳定性
+func TestHeuristicSummarizer(t *testing.T) {
+	day := time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)
+	commits := []git.CommitInfo{
+		{Hash: "c3", Date: day.Add(3 * time.Hour), Message: "fix(api): 修复超时", RepoPath: "backend"},
+		{Hash: "c2", Date: day.Add(2 * time.Hour), Message: "feat: 新增登录页", RepoPath: "frontend", Branches: []string{"main"}},
+		{Hash: "c1", Date: day.Add(1 * time.Hour), Message: "feat(api)!: 新的认证接口", RepoPath: "backend"},
+		{Hash: "c4", Date: day.Add(4 * time.Hour), Message: "调整配置", RepoPath: "backend"},
+	}
+
+	summarizer := NewHeuristicSummarizer()
+	summary, err := summarizer.SummarizeCommitsWithPrompt(context.Background(), commits, BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+
+	expected := `共 4 条提交，涉及 2 个仓库，时间范围 2025-05-20 至 2025-05-20。
+
+按类型统计：新功能 2 条，问题修复 1 条，其他 1 条
+包含 1 条破坏性变更
+
+### backend (3 条提交)
+
+#### 分支: 未标记分支
+- 新功能 (1):
+  - api: 新的认证接口 [破坏性变更]
+- 问题修复 (1):
+  - api: 修复超时
+- 其他 (1):
+  - 调整配置
+
+### frontend (1 条提交)
+
+#### 分支: main
+- 新功能 (1):
+  - 新增登录页
+`
+	if summary != expected {
+		t.Errorf("摘要不正确:\n期望:\n%s\n得到:\n%s", expected, summary)
+	}
+
+	// 输入顺序不同时输出应该相同
+	reversed := []git.CommitInfo{commits[3], commits[2], commits[1], commits[0]}
+	again, _ := summarizer.SummarizeCommitsWithPrompt(context.Background(), reversed, DetailedPrompt, PromptInfo{})
+	if again != summary {
+		t.Error("相同的提交记录应该生成相同的摘要")
+	}
+}
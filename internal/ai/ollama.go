@@ -0,0 +1,197 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/structured"
+)
+
+// Ollama的默认配置
+const (
+	// DefaultOllamaBaseURL 默认的本地Ollama服务地址
+	DefaultOllamaBaseURL = "http://localhost:11434"
+	// DefaultOllamaModelName 默认的模型名称
+	DefaultOllamaModelName = "llama3.1"
+)
+
+// DefaultOllamaMaxPromptTokens Ollama单个提示词默认的token上限，本地模型的上下文窗口通常只有8K左右
+const DefaultOllamaMaxPromptTokens = 6000
+
+// DefaultOllamaTimeout Ollama默认的单次请求超时时间，本地模型生成较慢，因此设置得比较长
+const DefaultOllamaTimeout = 10 * time.Minute
+
+// OllamaClient 是本地Ollama HTTP API的客户端，提交记录不会离开本机
+type OllamaClient struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+
+	chunkStrategy   ChunkStrategy
+	maxPromptTokens int
+	retry           RetryPolicy
+}
+
+// 确保OllamaClient实现了StreamSummarizer和StructuredSummarizer接口
+var (
+	_ StreamSummarizer     = (*OllamaClient)(nil)
+	_ StructuredSummarizer = (*OllamaClient)(nil)
+)
+
+func init() {
+	RegisterProvider(ProviderOllama, func(cfg Config) (Summarizer, error) {
+		return NewOllamaClient(cfg)
+	})
+}
+
+// NewOllamaClient 根据配置创建一个新的Ollama客户端
+func NewOllamaClient(cfg Config) (*OllamaClient, error) {
+	// 地址优先级：配置 > OLLAMA_HOST环境变量 > 默认地址
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("OLLAMA_HOST")
+	}
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+	// OLLAMA_HOST允许省略协议，如 127.0.0.1:11434
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+
+	modelName := cfg.ModelName
+	if modelName == "" {
+		modelName = DefaultOllamaModelName
+	}
+
+	return &OllamaClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      modelName,
+		httpClient: &http.Client{},
+
+		chunkStrategy:   cfg.ChunkStrategy,
+		retry:           cfg.retryPolicy(DefaultOllamaTimeout),
+		maxPromptTokens: cfg.maxPromptTokensOrDefault(DefaultOllamaMaxPromptTokens),
+	}, nil
+}
+
+// ollamaGenerateRequest /api/generate 请求体
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+	Format string `json:"format,omitempty"` // 为json时以JSON模式输出
+}
+
+// ollamaGenerateResponse /api/generate 响应体
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SummarizeCommitsWithPrompt 使用指定的提示词类型总结提交记录
+func (c *OllamaClient) SummarizeCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error) {
+	if len(commits) == 0 {
+		return noCommitsSummary, nil
+	}
+
+	return summarizeCommits(ctx, commits, promptType, info, c.chunkStrategy, c.maxPromptTokens, c.retry.wrap(c.generate), nil)
+}
+
+// StreamCommitsWithPrompt 使用指定的提示词类型总结提交记录，并将最终结果实时写入w
+func (c *OllamaClient) StreamCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, w io.Writer) (string, error) {
+	if len(commits) == 0 {
+		fmt.Fprint(w, noCommitsSummary)
+		return noCommitsSummary, nil
+	}
+
+	stream := c.retry.wrapStream(func(ctx context.Context, prompt string, w io.Writer) (string, error) {
+		return c.request(ctx, ollamaGenerateRequest{Model: c.model, Prompt: prompt, Stream: true}, w)
+	}, w)
+	return summarizeCommits(ctx, commits, promptType, info, c.chunkStrategy, c.maxPromptTokens, c.retry.wrap(c.generate), stream)
+}
+
+// SummarizeCommitsStructured 使用JSON模式生成结构化的摘要
+func (c *OllamaClient) SummarizeCommitsStructured(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (*structured.Summary, error) {
+	return summarizeStructured(ctx, commits, promptType, info, c.chunkStrategy, c.maxPromptTokens, c.retry.wrap(c.generate), c.retry.wrap(c.generateJSON))
+}
+
+// generate 调用/api/generate生成回复
+func (c *OllamaClient) generate(ctx context.Context, prompt string) (string, error) {
+	return c.request(ctx, ollamaGenerateRequest{Model: c.model, Prompt: prompt}, io.Discard)
+}
+
+// generateJSON 以JSON模式调用/api/generate
+func (c *OllamaClient) generateJSON(ctx context.Context, prompt string) (string, error) {
+	return c.request(ctx, ollamaGenerateRequest{Model: c.model, Prompt: prompt, Format: "json"}, io.Discard)
+}
+
+// request 调用/api/generate，将生成的内容写入w并返回完整的回复。
+// 非流式响应是单个JSON对象，流式响应是每行一个JSON对象，两者使用同样的方式解码
+func (c *OllamaClient) request(ctx context.Context, generateReq ollamaGenerateRequest, w io.Writer) (string, error) {
+	body, err := json.Marshal(generateReq)
+	if err != nil {
+		return "", fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("调用Ollama API失败 (请确认Ollama已在 %s 运行): %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// 读取错误信息
+		respBody, _ := io.ReadAll(resp.Body)
+		message := strings.TrimSpace(string(respBody))
+		var result ollamaGenerateResponse
+		if err := json.Unmarshal(respBody, &result); err == nil && result.Error != "" {
+			message = result.Error
+		}
+		return "", &StatusError{Provider: "Ollama", StatusCode: resp.StatusCode, Message: message}
+	}
+
+	var response strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk ollamaGenerateResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", fmt.Errorf("解析响应失败: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("Ollama生成失败: %s", chunk.Error)
+		}
+
+		response.WriteString(chunk.Response)
+		fmt.Fprint(w, chunk.Response)
+		if chunk.Done {
+			break
+		}
+	}
+
+	return response.String(), nil
+}
+
+// Close 关闭客户端
+func (c *OllamaClient) Close() {
+	c.httpClient.CloseIdleConnections()
+}
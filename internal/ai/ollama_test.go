@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestOllamaClientSummarize 测试Ollama客户端的请求和响应处理
+func TestOllamaClientSummarize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("请求路径应为 /api/generate, 得到: %s", r.URL.Path)
+		}
+
+		var req ollamaGenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("解析请求失败: %v", err)
+		}
+		if req.Model != DefaultOllamaModelName {
+			t.Errorf("模型应为默认模型 %s, 得到: %s", DefaultOllamaModelName, req.Model)
+		}
+		if req.Stream {
+			t.Error("不应该请求流式输出")
+		}
+		if !strings.Contains(req.Prompt, "feat: 添加周报功能") {
+			t.Errorf("提示词应包含提交消息, 得到: %s", req.Prompt)
+		}
+
+		_, _ = w.Write([]byte(`{"response":"本周完成了周报功能","done":true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOllamaClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	summary, err := client.SummarizeCommitsWithPrompt(context.Background(), testCommits(), BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if summary != "本周完成了周报功能" {
+		t.Errorf("摘要不正确: %q", summary)
+	}
+}
+
+// TestOllamaClientBaseURL 测试Ollama服务地址的解析
+func TestOllamaClientBaseURL(t *testing.T) {
+	t.Setenv("OLLAMA_HOST", "")
+	client, _ := NewOllamaClient(Config{})
+	if client.baseURL != DefaultOllamaBaseURL {
+		t.Errorf("默认地址应为 %s, 得到: %s", DefaultOllamaBaseURL, client.baseURL)
+	}
+
+	// OLLAMA_HOST可以省略协议
+	t.Setenv("OLLAMA_HOST", "127.0.0.1:11500")
+	client, _ = NewOllamaClient(Config{})
+	if client.baseURL != "http://127.0.0.1:11500" {
+		t.Errorf("地址应为 http://127.0.0.1:11500, 得到: %s", client.baseURL)
+	}
+
+	// 配置的地址优先于环境变量
+	client, _ = NewOllamaClient(Config{BaseURL: "http://gpu-box:11434/"})
+	if client.baseURL != "http://gpu-box:11434" {
+		t.Errorf("地址应为 http://gpu-box:11434, 得到: %s", client.baseURL)
+	}
+}
+
+// TestOllamaClientStream 测试流式输出
+func TestOllamaClientStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"response":"本周","done":false}
+{"response":"完成了周报功能","done":false}
+{"response":"","done":true}
+`))
+	}))
+	defer server.Close()
+
+	client, _ := NewOllamaClient(Config{BaseURL: server.URL})
+	defer client.Close()
+
+	var streamed strings.Builder
+	summary, err := client.StreamCommitsWithPrompt(context.Background(), testCommits(), BasicPrompt, PromptInfo{}, &streamed)
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if summary != "本周完成了周报功能" || streamed.String() != summary {
+		t.Errorf("流式输出和返回的摘要应该一致, 得到: %q, %q", streamed.String(), summary)
+	}
+}
@@ -0,0 +1,274 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/structured"
+)
+
+// OpenAI兼容服务的默认配置
+const (
+	// DefaultOpenAIBaseURL 默认的API基础地址
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+	// DefaultOpenAIModelName 默认的模型名称
+	DefaultOpenAIModelName = "gpt-4o-mini"
+	// DefaultOpenAIAPIKeyEnv 默认保存API密钥的环境变量
+	DefaultOpenAIAPIKeyEnv = "OPENAI_API_KEY"
+)
+
+// DefaultOpenAIMaxPromptTokens OpenAI兼容服务单个提示词默认的token上限，适用于128K上下文的模型
+const DefaultOpenAIMaxPromptTokens = 100000
+
+// OpenAIClient 是兼容OpenAI /v1/chat/completions 协议的客户端，
+// 可用于OpenAI、DeepSeek、vLLM、LM Studio等服务
+type OpenAIClient struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+
+	chunkStrategy   ChunkStrategy
+	maxPromptTokens int
+	retry           RetryPolicy
+}
+
+// 确保OpenAIClient实现了StreamSummarizer和StructuredSummarizer接口
+var (
+	_ StreamSummarizer     = (*OpenAIClient)(nil)
+	_ StructuredSummarizer = (*OpenAIClient)(nil)
+)
+
+func init() {
+	RegisterProvider(ProviderOpenAI, func(cfg Config) (Summarizer, error) {
+		return NewOpenAIClient(cfg)
+	})
+}
+
+// NewOpenAIClient 根据配置创建一个新的OpenAI兼容客户端
+func NewOpenAIClient(cfg Config) (*OpenAIClient, error) {
+	// 去掉末尾的斜杠，https://api.openai.com/v1/ 与默认地址视为相同
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	modelName := cfg.ModelName
+	if modelName == "" {
+		modelName = DefaultOpenAIModelName
+	}
+	apiKeyEnv := cfg.APIKeyEnv
+	if apiKeyEnv == "" {
+		apiKeyEnv = DefaultOpenAIAPIKeyEnv
+	}
+
+	// 自建服务（如vLLM、LM Studio）通常不需要API密钥，因此密钥为空时不报错
+	apiKey := os.Getenv(apiKeyEnv)
+	if apiKey == "" && baseURL == DefaultOpenAIBaseURL {
+		return nil, fmt.Errorf("未设置%s环境变量", apiKeyEnv)
+	}
+
+	return &OpenAIClient{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		model:      modelName,
+		httpClient: &http.Client{},
+
+		chunkStrategy:   cfg.ChunkStrategy,
+		retry:           cfg.retryPolicy(DefaultTimeout),
+		maxPromptTokens: cfg.maxPromptTokensOrDefault(DefaultOpenAIMaxPromptTokens),
+	}, nil
+}
+
+// chatMessage 对话消息
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatCompletionRequest chat completions 请求体
+type chatCompletionRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	Stream         bool            `json:"stream,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+// responseFormat 指定响应格式，{"type": "json_object"} 表示JSON模式
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+// chatCompletionResponse chat completions 响应体
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// chatCompletionChunk 流式响应中的一个数据块
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta chatMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+// SummarizeCommitsWithPrompt 使用指定的提示词类型总结提交记录
+func (c *OpenAIClient) SummarizeCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error) {
+	if len(commits) == 0 {
+		return noCommitsSummary, nil
+	}
+
+	return summarizeCommits(ctx, commits, promptType, info, c.chunkStrategy, c.maxPromptTokens, c.retry.wrap(c.complete), nil)
+}
+
+// StreamCommitsWithPrompt 使用指定的提示词类型总结提交记录，并将最终结果实时写入w
+func (c *OpenAIClient) StreamCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, w io.Writer) (string, error) {
+	if len(commits) == 0 {
+		fmt.Fprint(w, noCommitsSummary)
+		return noCommitsSummary, nil
+	}
+
+	stream := c.retry.wrapStream(c.completeStream, w)
+	return summarizeCommits(ctx, commits, promptType, info, c.chunkStrategy, c.maxPromptTokens, c.retry.wrap(c.complete), stream)
+}
+
+// SummarizeCommitsStructured 使用JSON模式生成结构化的摘要
+func (c *OpenAIClient) SummarizeCommitsStructured(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (*structured.Summary, error) {
+	return summarizeStructured(ctx, commits, promptType, info, c.chunkStrategy, c.maxPromptTokens, c.retry.wrap(c.complete), c.retry.wrap(c.completeJSON))
+}
+
+// complete 发送一次chat completions请求并返回模型的回复
+func (c *OpenAIClient) complete(ctx context.Context, prompt string) (string, error) {
+	return c.completeRequest(ctx, c.newRequest(prompt))
+}
+
+// completeJSON 以JSON模式发送chat completions请求
+func (c *OpenAIClient) completeJSON(ctx context.Context, prompt string) (string, error) {
+	req := c.newRequest(prompt)
+	req.ResponseFormat = &responseFormat{Type: "json_object"}
+	return c.completeRequest(ctx, req)
+}
+
+// newRequest 创建只包含一条用户消息的请求
+func (c *OpenAIClient) newRequest(prompt string) chatCompletionRequest {
+	return chatCompletionRequest{
+		Model:    c.model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+	}
+}
+
+// completeRequest 发送chat completions请求并返回模型的回复
+func (c *OpenAIClient) completeRequest(ctx context.Context, chatReq chatCompletionRequest) (string, error) {
+	resp, err := c.post(ctx, chatReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("OpenAI兼容API没有返回任何结果")
+	}
+
+	// 只请求了一个候选回复，取第一个即可
+	return result.Choices[0].Message.Content, nil
+}
+
+// completeStream 以流式（Server-Sent Events）方式发送chat completions请求，
+// 将收到的内容实时写入w并返回完整的回复
+func (c *OpenAIClient) completeStream(ctx context.Context, prompt string, w io.Writer) (string, error) {
+	chatReq := c.newRequest(prompt)
+	chatReq.Stream = true
+	resp, err := c.post(ctx, chatReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("解析流式响应失败: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		content := chunk.Choices[0].Delta.Content
+		result.WriteString(content)
+		fmt.Fprint(w, content)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("读取流式响应失败: %w", err)
+	}
+
+	return result.String(), nil
+}
+
+// post 发送chat completions请求，状态码不是200时读取错误信息并返回StatusError
+func (c *OpenAIClient) post(ctx context.Context, chatReq chatCompletionRequest) (*http.Response, error) {
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("调用OpenAI兼容API失败: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	// 读取错误信息
+	respBody, _ := io.ReadAll(resp.Body)
+	message := strings.TrimSpace(string(respBody))
+	var result chatCompletionResponse
+	if err := json.Unmarshal(respBody, &result); err == nil && result.Error != nil && result.Error.Message != "" {
+		message = result.Error.Message
+	}
+	return nil, &StatusError{Provider: "OpenAI兼容", StatusCode: resp.StatusCode, Message: message}
+}
+
+// Close 关闭客户端
+func (c *OpenAIClient) Close() {
+	c.httpClient.CloseIdleConnections()
+}
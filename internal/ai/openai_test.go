@@ -0,0 +1,136 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// testCommits 测试用的提交记录
+func testCommits() []git.CommitInfo {
+	return []git.CommitInfo{
+		{
+			Hash:    "abcdef1234567890",
+			Author:  "John Doe",
+			Date:    time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC),
+			Message: "feat: 添加周报功能",
+		},
+	}
+}
+
+// TestOpenAIClientSummarize 测试OpenAI兼容客户端的请求和响应处理
+func TestOpenAIClientSummarize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("请求路径应为 /v1/chat/completions, 得到: %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization头不正确: %s", got)
+		}
+
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("解析请求失败: %v", err)
+		}
+		if req.Model != "test-model" {
+			t.Errorf("模型应为 'test-model', 得到: %s", req.Model)
+		}
+		if len(req.Messages) != 1 || !strings.Contains(req.Messages[0].Content, "feat: 添加周报功能") {
+			t.Errorf("提示词应包含提交消息, 得到: %+v", req.Messages)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"本周完成了周报功能"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_OPENAI_KEY", "test-key")
+	client, err := NewOpenAIClient(Config{
+		ModelName: "test-model",
+		BaseURL:   server.URL + "/v1/",
+		APIKeyEnv: "TEST_OPENAI_KEY",
+	})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	summary, err := client.SummarizeCommitsWithPrompt(context.Background(), testCommits(), BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if summary != "本周完成了周报功能" {
+		t.Errorf("摘要不正确: %q", summary)
+	}
+}
+
+// TestOpenAIClientError 测试API返回错误时的处理
+func TestOpenAIClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(Config{BaseURL: server.URL, APIKeyEnv: "TEST_OPENAI_KEY_UNSET"})
+	if err != nil {
+		t.Fatalf("自建服务不需要API密钥, 创建客户端不应失败: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.SummarizeCommitsWithPrompt(context.Background(), testCommits(), BasicPrompt, PromptInfo{})
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Errorf("应返回包含API错误信息的错误, 得到: %v", err)
+	}
+
+	// 使用官方地址时必须设置API密钥
+	t.Setenv(DefaultOpenAIAPIKeyEnv, "")
+	if _, err := NewOpenAIClient(Config{}); err == nil {
+		t.Error("未设置API密钥时应该返回错误")
+	}
+	if _, err := NewOpenAIClient(Config{BaseURL: DefaultOpenAIBaseURL + "/"}); err == nil {
+		t.Error("官方地址末尾带斜杠时也应该要求API密钥")
+	}
+}
+
+// TestOpenAIClientStream 测试流式输出
+func TestOpenAIClientStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("解析请求失败: %v", err)
+		}
+		if !req.Stream {
+			t.Error("应该请求流式输出")
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{"本周", "完成了", "周报功能"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	var streamed strings.Builder
+	summary, err := client.StreamCommitsWithPrompt(context.Background(), testCommits(), BasicPrompt, PromptInfo{}, &streamed)
+	if err != nil {
+		t.Fatalf("生成摘要失败: %v", err)
+	}
+	if summary != "本周完成了周报功能" || streamed.String() != summary {
+		t.Errorf("流式输出和返回的摘要应该一致, 得到: %q, %q", streamed.String(), summary)
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/prompts"
 )
 
 // PromptType 表示不同类型的提示词
@@ -76,3 +81,209 @@ func LoadCustomPrompt(filePath string) (string, error) {
 
 	return promptContent, nil
 }
+
+// BuiltinPromptSource 内置提示词的来源名称
+const BuiltinPromptSource = "内置"
+
+// promptDirs 查找提示词覆盖文件的目录，前面的目录优先
+var promptDirs []string
+
+// SetPromptDirs 设置查找提示词覆盖文件的目录，前面的目录优先。
+// 目录中与预设提示词同名的文件（如detailed.txt）会覆盖内置的提示词，
+// 其他文件可以通过文件名（如 --prompt kpi 对应 kpi.md 或 kpi.txt）作为自定义提示词使用
+func SetPromptDirs(dirs []string) {
+	promptDirs = dirs
+}
+
+// UserPromptDir 返回用户配置目录下的提示词目录，如Linux上的 ~/.config/git-work-log/prompts
+func UserPromptDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户配置目录失败: %w", err)
+	}
+	return filepath.Join(configDir, "git-work-log", "prompts"), nil
+}
+
+// RepoPromptDir 返回仓库内的提示词目录 .git-work-log/prompts
+func RepoPromptDir(repoPath string) string {
+	return filepath.Join(repoPath, ".git-work-log", "prompts")
+}
+
+// PromptDirs 按查找顺序返回提示词目录：flagDir（--prompts-dir）、用户配置目录、仓库内的.git-work-log/prompts
+func PromptDirs(flagDir, repoPath string) []string {
+	var dirs []string
+	if flagDir != "" {
+		dirs = append(dirs, flagDir)
+	}
+	if userDir, err := UserPromptDir(); err == nil {
+		dirs = append(dirs, userDir)
+	}
+	return append(dirs, RepoPromptDir(repoPath))
+}
+
+// presetPromptFile 返回预设提示词对应的文件名，中文为basic.txt，其他语言为basic.en.txt
+func presetPromptFile(promptType PromptType, lang i18n.Language) string {
+	name := string(BasicPrompt)
+	if promptType == DetailedPrompt || promptType == TargetedPrompt {
+		name = string(promptType)
+	}
+
+	if lang == "" || lang == i18n.Chinese {
+		return name + ".txt"
+	}
+	return name + "." + string(lang) + ".txt"
+}
+
+// BuiltinPrompt 返回指定语言的内置预设提示词，不考虑提示词目录中的覆盖文件
+func BuiltinPrompt(promptType PromptType, lang i18n.Language) (string, error) {
+	content, err := prompts.FS.ReadFile(presetPromptFile(promptType, lang))
+	if err != nil {
+		return "", fmt.Errorf("读取内置提示词失败: %w", err)
+	}
+	return string(content), nil
+}
+
+// ResolvePrompt 查找提示词模板，返回模板内容和来源（文件路径或"内置"）。
+// 预设提示词依次查找各提示词目录中对应语言的同名文件，都没有时使用内置的提示词；
+// 自定义提示词先按文件路径加载，文件不存在时在提示词目录中按名称查找，自定义提示词不区分语言
+func ResolvePrompt(promptType PromptType, lang i18n.Language) (string, string, error) {
+	if !IsCustomPrompt(promptType) {
+		filename := presetPromptFile(promptType, lang)
+		if content, path, ok := findPromptFile(filename); ok {
+			return content, path, nil
+		}
+
+		content, err := BuiltinPrompt(promptType, lang)
+		if err != nil {
+			return "", "", err
+		}
+		return content, BuiltinPromptSource, nil
+	}
+
+	name := string(promptType)
+	content, err := LoadCustomPrompt(name)
+	if err == nil {
+		return content, name, nil
+	}
+
+	// 不是文件路径时，在提示词目录中按名称查找
+	if !strings.ContainsRune(name, filepath.Separator) {
+		for _, filename := range []string{name, name + ".md", name + ".txt"} {
+			if content, path, ok := findPromptFile(filename); ok {
+				return content, path, nil
+			}
+		}
+	}
+	return "", "", err
+}
+
+// findPromptFile 在提示词目录中按优先级查找文件，返回第一个非空文件的内容和路径
+func findPromptFile(filename string) (string, string, bool) {
+	for _, dir := range promptDirs {
+		path := filepath.Join(dir, filename)
+		content, err := loadPromptTemplateFromPath(path)
+		if err == nil && strings.TrimSpace(string(content)) != "" {
+			return string(content), path, true
+		}
+	}
+	return "", "", false
+}
+
+// loadPromptTemplate 加载指定语言的提示词模板，自定义提示词没有引用提交记录时在末尾添加
+func loadPromptTemplate(promptType PromptType, lang i18n.Language) (string, error) {
+	content, _, err := ResolvePrompt(promptType, lang)
+	if err != nil {
+		if IsCustomPrompt(promptType) {
+			return "", fmt.Errorf("加载自定义提示词失败: %w", err)
+		}
+		return "", err
+	}
+
+	// 确保自定义提示词包含提交记录，模板中没有引用提交记录时在末尾添加
+	if IsCustomPrompt(promptType) && !ReferencesCommits(content) {
+		content += localized(lang, "\n\n提交记录：\n{{.CommitMessages}}", "\n\nCommits:\n{{.CommitMessages}}")
+	}
+
+	return content, nil
+}
+
+// loadPromptTemplateFromPath 从指定路径加载提示词模板
+func loadPromptTemplateFromPath(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// PromptTemplate 提示词库中的一个提示词模板
+type PromptTemplate struct {
+	Name   string // 名称，可以直接用于--prompt
+	Source string // 来源：文件路径或"内置"
+	Preset bool   // 是否为预设提示词
+}
+
+// ListPrompts 列出指定语言的预设提示词（显示实际使用的来源）和各提示词目录中的其他提示词文件，
+// 同名文件只列出优先级最高的一个
+func ListPrompts(lang i18n.Language) ([]PromptTemplate, error) {
+	var templates []PromptTemplate
+	seen := make(map[string]bool)
+	for _, promptType := range []PromptType{BasicPrompt, DetailedPrompt, TargetedPrompt} {
+		_, source, err := ResolvePrompt(promptType, lang)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, PromptTemplate{Name: string(promptType), Source: source, Preset: true})
+		seen[string(promptType)] = true
+	}
+
+	for _, dir := range promptDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("读取提示词目录失败: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			templates = append(templates, PromptTemplate{Name: name, Source: filepath.Join(dir, entry.Name())})
+		}
+	}
+	return templates, nil
+}
+
+// ValidatePrompt 加载并校验指定语言的提示词模板
+func ValidatePrompt(promptType PromptType, lang i18n.Language) error {
+	content, err := loadPromptTemplate(promptType, lang)
+	if err != nil {
+		return err
+	}
+	return ValidatePromptTemplate(content)
+}
+
+// ValidatePromptTemplate 校验提示词模板的语法，并使用示例数据试渲染，
+// 以便在调用AI之前发现引用不存在的变量等错误
+func ValidatePromptTemplate(content string) error {
+	sample := []git.CommitInfo{{
+		Hash:         "0123456789abcdef",
+		Author:       "示例作者",
+		Date:         time.Date(2025, 5, 20, 10, 0, 0, 0, time.Local),
+		Message:      "feat: 示例提交",
+		Branches:     []string{"main"},
+		ChangedFiles: []string{"main.go"},
+		RepoPath:     "example",
+	}}
+	_, err := renderPromptTemplate(content, newPromptData(sample, PromptInfo{Author: "示例作者"}))
+	return err
+}
+
+// ReferencesCommits 检查提示词模板是否引用了提交记录（.CommitMessages或.Commits），
+// 没有引用时自定义提示词会在末尾自动追加提交记录
+func ReferencesCommits(content string) bool {
+	return strings.Contains(content, ".CommitMessages") || strings.Contains(content, ".Commits")
+}
@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// TestResolvePrompt 测试内置提示词和提示词目录的查找顺序
+func TestResolvePrompt(t *testing.T) {
+	defer SetPromptDirs(nil)
+
+	// 没有覆盖文件时使用内置的提示词
+	SetPromptDirs(nil)
+	for _, promptType := range []PromptType{BasicPrompt, DetailedPrompt, TargetedPrompt} {
+		content, source, err := ResolvePrompt(promptType, i18n.Chinese)
+		if err != nil || source != BuiltinPromptSource || !strings.Contains(content, "{{.CommitMessages}}") {
+			t.Errorf("%s 应使用内置提示词, 得到来源: %s (err: %v)", promptType, source, err)
+		}
+	}
+
+	// 前面的目录优先
+	flagDir, userDir := t.TempDir(), t.TempDir()
+	writePrompt(t, flagDir, "detailed.txt", "命令行目录 {{.CommitMessages}}")
+	writePrompt(t, userDir, "detailed.txt", "用户目录 {{.CommitMessages}}")
+	writePrompt(t, userDir, "kpi.md", "KPI {{.CommitMessages}}")
+	SetPromptDirs([]string{flagDir, userDir})
+
+	content, source, err := ResolvePrompt(DetailedPrompt, i18n.Chinese)
+	if err != nil || !strings.HasPrefix(content, "命令行目录") || source != filepath.Join(flagDir, "detailed.txt") {
+		t.Errorf("应使用--prompts-dir中的提示词, 得到: %q, %s (err: %v)", content, source, err)
+	}
+	if content, _, _ := ResolvePrompt(BasicPrompt, i18n.Chinese); !strings.Contains(content, "{{.CommitMessages}}") {
+		t.Error("没有覆盖的预设提示词应使用内置提示词")
+	}
+
+	// 自定义提示词可以按名称在提示词目录中查找
+	content, _, err = ResolvePrompt(PromptType("kpi"), i18n.Chinese)
+	if err != nil || !strings.HasPrefix(content, "KPI") {
+		t.Errorf("应找到提示词目录中的kpi.md, 得到: %q (err: %v)", content, err)
+	}
+	if _, _, err := ResolvePrompt(PromptType("missing"), i18n.Chinese); err == nil {
+		t.Error("不存在的自定义提示词应返回错误")
+	}
+}
+
+// writePrompt 在目录中写入提示词文件
+func writePrompt(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("写入提示词文件失败: %v", err)
+	}
+}
+
+// TestListAndValidatePrompts 测试列出提示词库和校验提示词模板
+func TestListAndValidatePrompts(t *testing.T) {
+	defer SetPromptDirs(nil)
+
+	dir := t.TempDir()
+	writePrompt(t, dir, "basic.txt", "覆盖 {{.CommitMessages}}")
+	writePrompt(t, dir, "kpi.md", "{{.ReportType}} {{range .Commits}}{{.Message}}{{end}}")
+	writePrompt(t, dir, "broken.txt", "{{.Unknown}}")
+	SetPromptDirs([]string{dir})
+
+	templates, err := ListPrompts(i18n.Chinese)
+	if err != nil {
+		t.Fatalf("列出提示词失败: %v", err)
+	}
+	if len(templates) != 5 || templates[0].Source != filepath.Join(dir, "basic.txt") || templates[1].Source != BuiltinPromptSource {
+		t.Errorf("提示词列表不正确: %+v", templates)
+	}
+
+	if err := ValidatePrompt(PromptType("kpi"), i18n.Chinese); err != nil {
+		t.Errorf("kpi应校验通过: %v", err)
+	}
+	if err := ValidatePrompt(PromptType("broken"), i18n.Chinese); err == nil {
+		t.Error("引用不存在的变量应校验失败")
+	}
+	if ReferencesCommits("没有提交记录") || !ReferencesCommits("{{range .Commits}}{{end}}") {
+		t.Error("ReferencesCommits结果不正确")
+	}
+}
@@ -0,0 +1,238 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// 默认的重试配置
+const (
+	// DefaultMaxRetries 默认的最大重试次数
+	DefaultMaxRetries = 3
+	// DefaultTimeout 默认的单次请求超时时间
+	DefaultTimeout = 2 * time.Minute
+
+	defaultRetryBaseDelay = time.Second
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy AI请求的超时和重试策略
+type RetryPolicy struct {
+	MaxRetries int           // 最大重试次数，0表示不重试
+	Timeout    time.Duration // 单次请求的超时时间，0表示不限制
+	BaseDelay  time.Duration // 第一次重试前的等待时间，之后按指数增长
+	MaxDelay   time.Duration // 重试等待时间的上限
+}
+
+// StatusError 表示AI服务返回的非成功HTTP状态
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+// Error 实现error接口
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("调用%s API失败: HTTP %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// wrap 为生成函数加上超时和重试
+func (p RetryPolicy) wrap(generate generateFunc) generateFunc {
+	return func(ctx context.Context, prompt string) (string, error) {
+		var lastErr error
+		for attempt := 0; attempt <= p.MaxRetries; attempt++ {
+			if attempt > 0 {
+				delay := p.backoff(attempt)
+				i18n.Printf("  AI请求失败: %v，%s后进行第 %d/%d 次重试\n", lastErr, delay.Round(time.Millisecond), attempt, p.MaxRetries)
+				if err := sleep(ctx, delay); err != nil {
+					return "", err
+				}
+			}
+
+			result, err := p.attempt(ctx, generate, prompt)
+			if err == nil {
+				return result, nil
+			}
+			lastErr = err
+
+			// 用户取消或者错误不可重试时直接返回
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			if !isRetryable(err) {
+				return "", err
+			}
+		}
+
+		return "", fmt.Errorf("重试 %d 次后仍然失败: %w", p.MaxRetries, lastErr)
+	}
+}
+
+// streamFunc 以流式方式生成文本，将收到的内容实时写入w并返回完整的回复
+type streamFunc func(ctx context.Context, prompt string, w io.Writer) (string, error)
+
+// errStreamFirstByteTimeout 流式请求在超时时间内没有收到任何内容
+var errStreamFirstByteTimeout = fmt.Errorf("等待AI流式响应超时: %w", context.DeadlineExceeded)
+
+// wrapStream 为流式生成函数加上超时和重试。只有还没有向w写入任何内容时才重试，
+// 开始输出之后失败直接返回错误，避免重复输出已经写入的部分。
+// 流式生成的总时长取决于回复的长度，因此超时时间只限制收到第一段内容之前的等待
+func (p RetryPolicy) wrapStream(stream streamFunc, w io.Writer) generateFunc {
+	firstByteTimeout := p.Timeout
+	p.Timeout = 0
+	return p.wrap(func(ctx context.Context, prompt string) (string, error) {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		sw := &streamWriter{w: w}
+		if firstByteTimeout > 0 {
+			timer := time.AfterFunc(firstByteTimeout, func() { cancel(errStreamFirstByteTimeout) })
+			defer timer.Stop()
+			sw.onFirstWrite = func() { timer.Stop() }
+		}
+
+		result, err := stream(ctx, prompt, sw)
+		switch {
+		case err == nil:
+			return result, nil
+		case sw.written > 0:
+			return "", &streamInterruptedError{Err: err}
+		case errors.Is(context.Cause(ctx), errStreamFirstByteTimeout):
+			return "", errStreamFirstByteTimeout
+		default:
+			return "", err
+		}
+	})
+}
+
+// streamWriter 记录已经写入的字节数，第一次写入内容时调用onFirstWrite
+type streamWriter struct {
+	w            io.Writer
+	written      int
+	onFirstWrite func()
+}
+
+// Write 实现io.Writer接口
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if s.written == 0 && len(p) > 0 && s.onFirstWrite != nil {
+		s.onFirstWrite()
+	}
+	n, err := s.w.Write(p)
+	s.written += n
+	return n, err
+}
+
+// streamInterruptedError 表示流式输出开始之后中断，这种错误不会重试
+type streamInterruptedError struct {
+	Err error
+}
+
+// Error 实现error接口
+func (e *streamInterruptedError) Error() string {
+	return fmt.Sprintf("流式输出中断: %v", e.Err)
+}
+
+// Unwrap 返回导致中断的错误
+func (e *streamInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// attempt 在单次请求的超时时间内调用生成函数
+func (p RetryPolicy) attempt(ctx context.Context, generate generateFunc, prompt string) (string, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	return generate(ctx, prompt)
+}
+
+// backoff 计算第attempt次重试前的等待时间：指数退避，并加入随机抖动避免同时重试
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+
+	// 在 [delay/2, delay) 之间随机取值
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// sleep 等待指定时间，context取消时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryable 判断错误是否为可重试的临时错误（限流、服务端错误、超时、连接重置）
+func isRetryable(err error) bool {
+	// 流式输出已经开始，重试会重复输出已经写入的内容
+	var interruptedErr *streamInterruptedError
+	if errors.As(err, &interruptedErr) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableStatus(statusErr.StatusCode)
+	}
+
+	// Gemini SDK返回的错误（apierror.APIError）提供HTTPCode方法
+	var httpErr interface{ HTTPCode() int }
+	if errors.As(err, &httpErr) && httpErr.HTTPCode() > 0 {
+		return isRetryableStatus(httpErr.HTTPCode())
+	}
+
+	// 单次请求超时
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET)
+}
+
+// isRetryableStatus 判断HTTP状态码是否可重试
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,192 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicy 测试可重试错误的重试和不可重试错误的立即返回
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	// 两次503之后成功
+	calls := 0
+	result, err := policy.wrap(func(_ context.Context, _ string) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", &StatusError{Provider: "测试", StatusCode: http.StatusServiceUnavailable}
+		}
+		return "成功", nil
+	})(context.Background(), "prompt")
+	if err != nil || result != "成功" || calls != 3 {
+		t.Errorf("应在第3次调用时成功, 得到: %q, %v, 调用 %d 次", result, err, calls)
+	}
+
+	// 超过最大重试次数
+	calls = 0
+	_, err = policy.wrap(func(_ context.Context, _ string) (string, error) {
+		calls++
+		return "", &StatusError{Provider: "测试", StatusCode: http.StatusTooManyRequests}
+	})(context.Background(), "prompt")
+	if err == nil || calls != 3 {
+		t.Errorf("应调用3次后返回错误, 得到: %v, 调用 %d 次", err, calls)
+	}
+
+	// 不可重试的错误立即返回
+	calls = 0
+	_, err = policy.wrap(func(_ context.Context, _ string) (string, error) {
+		calls++
+		return "", &StatusError{Provider: "测试", StatusCode: http.StatusUnauthorized}
+	})(context.Background(), "prompt")
+	if err == nil || calls != 1 {
+		t.Errorf("不可重试的错误应只调用1次, 得到: %v, 调用 %d 次", err, calls)
+	}
+}
+
+// TestRetryPolicyTimeout 测试单次请求超时后重试，以及取消时立即返回
+func TestRetryPolicyTimeout(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 1, Timeout: 10 * time.Millisecond, BaseDelay: time.Millisecond}
+
+	calls := 0
+	result, err := policy.wrap(func(ctx context.Context, _ string) (string, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "成功", nil
+	})(context.Background(), "prompt")
+	if err != nil || result != "成功" {
+		t.Errorf("超时后重试应成功, 得到: %q, %v", result, err)
+	}
+
+	// 取消后不再重试
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	_, err = policy.wrap(func(_ context.Context, _ string) (string, error) {
+		calls++
+		cancel()
+		return "", &StatusError{Provider: "测试", StatusCode: http.StatusServiceUnavailable}
+	})(ctx, "prompt")
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("取消后应返回context.Canceled, 得到: %v, 调用 %d 次", err, calls)
+	}
+}
+
+// TestRetryPolicyStream 测试流式输出开始之前失败时重试，开始之后失败时不重试
+func TestRetryPolicyStream(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, Timeout: 20 * time.Millisecond, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	// 输出第一段之后失败：直接返回错误，不重复输出
+	calls := 0
+	var output strings.Builder
+	_, err := policy.wrapStream(func(_ context.Context, _ string, w io.Writer) (string, error) {
+		calls++
+		fmt.Fprint(w, "本周")
+		return "", &StatusError{Provider: "测试", StatusCode: http.StatusServiceUnavailable}
+	}, &output)(context.Background(), "prompt")
+	var interruptedErr *streamInterruptedError
+	if !errors.As(err, &interruptedErr) || calls != 1 || output.String() != "本周" {
+		t.Errorf("开始输出后失败不应重试, 得到: %v, 调用 %d 次, 输出 %q", err, calls, output.String())
+	}
+
+	// 输出之前失败：重试
+	calls = 0
+	output.Reset()
+	result, err := policy.wrapStream(func(_ context.Context, _ string, w io.Writer) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", &StatusError{Provider: "测试", StatusCode: http.StatusServiceUnavailable}
+		}
+		fmt.Fprint(w, "本周完成了周报功能")
+		return "本周完成了周报功能", nil
+	}, &output)(context.Background(), "prompt")
+	if err != nil || calls != 2 || output.String() != result {
+		t.Errorf("输出之前失败应重试, 得到: %q, %v, 调用 %d 次, 输出 %q", result, err, calls, output.String())
+	}
+
+	// 超时时间只限制收到第一段内容之前的等待，之后的生成可以超过它
+	output.Reset()
+	result, err = policy.wrapStream(func(ctx context.Context, _ string, w io.Writer) (string, error) {
+		fmt.Fprint(w, "本周")
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(3 * policy.Timeout):
+		}
+		fmt.Fprint(w, "完成了周报功能")
+		return "本周完成了周报功能", nil
+	}, &output)(context.Background(), "prompt")
+	if err != nil || result != "本周完成了周报功能" {
+		t.Errorf("开始输出后不应受单次请求超时限制, 得到: %q, %v", result, err)
+	}
+
+	// 一直没有输出时超时并重试
+	calls = 0
+	_, err = policy.wrapStream(func(ctx context.Context, _ string, _ io.Writer) (string, error) {
+		calls++
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, io.Discard)(context.Background(), "prompt")
+	if !errors.Is(err, context.DeadlineExceeded) || calls != 3 {
+		t.Errorf("没有输出时应超时并重试, 得到: %v, 调用 %d 次", err, calls)
+	}
+}
+
+// TestIsRetryable 测试错误分类
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"限流", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"服务不可用", fmt.Errorf("包装: %w", &StatusError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"未授权", &StatusError{StatusCode: http.StatusUnauthorized}, false},
+		{"超时", context.DeadlineExceeded, true},
+		{"取消", context.Canceled, false},
+		{"普通错误", errors.New("解析失败"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isRetryable(test.err); got != test.expected {
+				t.Errorf("期望 %v, 得到 %v", test.expected, got)
+			}
+		})
+	}
+}
+
+// TestOpenAIClientRetry 测试OpenAI兼容客户端遇到429时重试
+func TestOpenAIClientRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"重试成功"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(Config{BaseURL: server.URL, MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	defer client.Close()
+	client.retry.BaseDelay = time.Millisecond
+
+	summary, err := client.SummarizeCommitsWithPrompt(context.Background(), testCommits(), BasicPrompt, PromptInfo{})
+	if err != nil || summary != "重试成功" {
+		t.Errorf("重试后应成功, 得到: %q, %v", summary, err)
+	}
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/internal/structured"
+)
+
+// StructuredSummarizer 是支持生成结构化摘要的Summarizer
+type StructuredSummarizer interface {
+	Summarizer
+	// SummarizeCommitsStructured 使用指定的提示词类型生成结构化的摘要
+	SummarizeCommitsStructured(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (*structured.Summary, error)
+}
+
+// structuredInstruction 追加在提示词末尾，要求模型按固定的JSON格式输出
+const structuredInstruction = `
+
+请按照以上要求分析提交记录，但只输出一个JSON对象，不要输出任何其他内容，格式如下：
+{
+  "overview": "总体工作概述（1-3句话）",
+  "achievements": ["完成的主要工作或成就"],
+  "in_progress": ["进行中的工作"],
+  "risks": ["风险、问题或需要关注的事项"],
+  "next_steps": ["下一步计划"]
+}
+没有内容的栏目请输出空数组。`
+
+// structuredInstructionEN 英文的JSON格式说明
+const structuredInstructionEN = `
+
+Analyze the commits as described above, but output only a single JSON object and nothing else, in the following format:
+{
+  "overview": "overall summary of the work (1-3 sentences)",
+  "achievements": ["main completed work or achievements"],
+  "in_progress": ["work in progress"],
+  "risks": ["risks, issues or items needing attention"],
+  "next_steps": ["next steps"]
+}
+Write all values in English. Use an empty array for sections without content.`
+
+// summarizeStructured 生成结构化摘要：分段总结与普通模式相同，最终结果使用generateJSON以JSON模式生成
+func summarizeStructured(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, strategy ChunkStrategy, maxTokens int, generate, generateJSON generateFunc) (*structured.Summary, error) {
+	if len(commits) == 0 {
+		summary := &structured.Summary{Overview: noCommitsSummary}
+		return summary, summary.Validate()
+	}
+
+	final := func(ctx context.Context, prompt string) (string, error) {
+		return generateJSON(ctx, withStructuredInstruction(prompt, info.Language))
+	}
+	text, err := summarizeCommits(ctx, commits, promptType, info, strategy, maxTokens, generate, final)
+	if err != nil {
+		return nil, err
+	}
+
+	return structured.Parse(text)
+}
+
+// withStructuredInstruction 在提示词末尾追加指定语言的JSON格式说明
+func withStructuredInstruction(prompt string, lang i18n.Language) string {
+	return prompt + localized(lang, structuredInstruction, structuredInstructionEN)
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// TestOpenAIClientStructured 测试OpenAI兼容客户端以JSON模式请求结构化摘要
+func TestOpenAIClientStructured(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("解析请求失败: %v", err)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+			t.Errorf("应使用JSON模式, 得到: %+v", req.ResponseFormat)
+		}
+		if !strings.Contains(req.Messages[0].Content, `"next_steps"`) {
+			t.Error("提示词应包含JSON格式说明")
+		}
+
+		content, _ := json.Marshal(`{"overview":"完成周报功能","achievements":["周报"],"in_progress":[],"risks":[],"next_steps":[]}`)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + string(content) + `}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAIClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	summary, err := client.SummarizeCommitsStructured(context.Background(), testCommits(), BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("生成结构化摘要失败: %v", err)
+	}
+	if summary.Overview != "完成周报功能" || len(summary.Achievements) != 1 {
+		t.Errorf("结构化摘要不正确: %+v", summary)
+	}
+	if !strings.Contains(summary.Markdown(i18n.Chinese), "### 风险与问题\n- 无") {
+		t.Errorf("空栏目应显示为无, 得到:\n%s", summary.Markdown(i18n.Chinese))
+	}
+}
+
+// TestHeuristicSummarizerStructured 测试规则摘要的结构化输出
+func TestHeuristicSummarizerStructured(t *testing.T) {
+	date := time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC)
+	commits := []git.CommitInfo{
+		{Hash: "a1", Date: date, Message: "feat(report): 添加周报"},
+		{Hash: "a2", Date: date, Message: "WIP: 导出PDF"},
+		{Hash: "a3", Date: date, Message: "refactor(api)!: 重命名接口"},
+		{Hash: "a4", Date: date, Message: "chore: 更新依赖"},
+	}
+
+	summary, err := NewHeuristicSummarizer().SummarizeCommitsStructured(context.Background(), commits, BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("生成结构化摘要失败: %v", err)
+	}
+
+	if len(summary.Achievements) != 2 || summary.Achievements[0] != "新功能: report: 添加周报" {
+		t.Errorf("主要成果不正确: %v", summary.Achievements)
+	}
+	if len(summary.InProgress) != 1 || len(summary.Risks) != 1 || len(summary.NextSteps) != 0 {
+		t.Errorf("结构化摘要不正确: %+v", summary)
+	}
+}
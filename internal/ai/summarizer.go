@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// 内置的AI提供方名称
+const (
+	// ProviderGemini Google Gemini
+	ProviderGemini = "gemini"
+	// ProviderOpenAI 兼容OpenAI Chat Completions协议的服务
+	ProviderOpenAI = "openai"
+	// ProviderOllama 本地Ollama服务
+	ProviderOllama = "ollama"
+	// ProviderHeuristic 基于规则的离线摘要，不调用任何AI服务
+	ProviderHeuristic = "heuristic"
+)
+
+// DefaultProvider 默认使用的AI提供方
+const DefaultProvider = ProviderGemini
+
+// Summarizer 是AI摘要后端的通用接口，每个AI提供方都需要实现该接口
+type Summarizer interface {
+	// SummarizeCommitsWithPrompt 使用指定的提示词类型总结提交记录，info是填入提示词模板的报告元信息，
+	// ctx取消时应尽快返回
+	SummarizeCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error)
+	// Close 释放后端占用的资源
+	Close()
+}
+
+// StreamSummarizer 是支持流式输出的Summarizer
+type StreamSummarizer interface {
+	Summarizer
+	// StreamCommitsWithPrompt 与SummarizeCommitsWithPrompt相同，但会在生成过程中把最终结果实时写入w，
+	// 分段总结时只有最后的合并步骤会写入w。返回完整的摘要
+	StreamCommitsWithPrompt(ctx context.Context, commits []git.CommitInfo, promptType PromptType, info PromptInfo, w io.Writer) (string, error)
+}
+
+// noCommitsSummary 没有提交记录时返回的摘要
+const noCommitsSummary = "没有找到提交记录。"
+
+// Config 创建AI后端时使用的配置
+type Config struct {
+	ModelName string // 模型名称，为空时使用提供方的默认模型
+	BaseURL   string // API基础地址，为空时使用提供方的默认地址
+	APIKeyEnv string // 保存API密钥的环境变量名，为空时使用提供方的默认变量
+
+	ChunkStrategy   ChunkStrategy // 提示词超出上限时的分块策略，为空时使用auto
+	MaxPromptTokens int           // 单个提示词的token上限，为0时使用提供方的默认值
+
+	Timeout    time.Duration // 单次请求的超时时间，为0时使用提供方的默认值
+	MaxRetries int           // 遇到限流、服务端错误等临时错误时的最大重试次数
+}
+
+// maxPromptTokensOrDefault 返回配置的token上限，未配置时返回提供方的默认值
+func (c Config) maxPromptTokensOrDefault(defaultTokens int) int {
+	if c.MaxPromptTokens > 0 {
+		return c.MaxPromptTokens
+	}
+	return defaultTokens
+}
+
+// retryPolicy 根据配置生成重试策略，未配置超时时间时使用提供方的默认值
+func (c Config) retryPolicy(defaultTimeout time.Duration) RetryPolicy {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return RetryPolicy{MaxRetries: c.MaxRetries, Timeout: timeout}
+}
+
+// ProviderFactory 根据配置创建一个Summarizer
+type ProviderFactory func(cfg Config) (Summarizer, error)
+
+// providers 已注册的AI提供方
+var providers = make(map[string]ProviderFactory)
+
+// RegisterProvider 注册一个AI提供方，同名的提供方会被覆盖
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers[strings.ToLower(name)] = factory
+}
+
+// Providers 返回所有已注册的提供方名称（按字母排序）
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSummarizer 根据提供方名称创建Summarizer，名称为空时使用默认提供方
+func NewSummarizer(provider string, cfg Config) (Summarizer, error) {
+	if provider == "" {
+		provider = DefaultProvider
+	}
+
+	factory, ok := providers[strings.ToLower(provider)]
+	if !ok {
+		return nil, fmt.Errorf("未知的AI提供方: %s (可选: %s)", provider, strings.Join(Providers(), ", "))
+	}
+
+	return factory(cfg)
+}
+
+// BuildPrompt 使用报告元信息构建发送给AI提供方的完整提示词，不调用任何AI服务，用于预览和调试提示词
+func BuildPrompt(commits []git.CommitInfo, promptType PromptType, info PromptInfo) (string, error) {
+	return buildPromptWithTemplate(commits, info, promptType)
+}
+
+// DefaultMaxPromptTokens 返回提供方默认的单个提示词token上限，名称为空时使用默认提供方，
+// 不发送提示词的提供方（如heuristic）返回0
+func DefaultMaxPromptTokens(provider string) int {
+	if provider == "" {
+		provider = DefaultProvider
+	}
+
+	switch strings.ToLower(provider) {
+	case ProviderGemini:
+		return DefaultGeminiMaxPromptTokens
+	case ProviderOpenAI:
+		return DefaultOpenAIMaxPromptTokens
+	case ProviderOllama:
+		return DefaultOllamaMaxPromptTokens
+	default:
+		return 0
+	}
+}
+
+// PromptPreview 生成报告时发送给AI提供方的提示词
+type PromptPreview struct {
+	Prompt    string // 完整的提示词，结构化模式下包含JSON格式说明
+	Tokens    int    // 估算的token数量，不包含JSON格式说明，用于判断是否需要分段
+	MaxTokens int    // 单个提示词的token上限，为0时不限制
+	Chunks    int    // 超出上限时分段总结的段数，不需要分段时为0
+}
+
+// PreviewPrompt 按照与生成报告相同的方式构建提示词：token上限未配置时使用提供方的默认值，
+// structured为true时追加结构化输出的JSON格式说明，超出上限时计算分段的段数。不调用任何AI服务
+func PreviewPrompt(commits []git.CommitInfo, promptType PromptType, info PromptInfo, provider string, cfg Config, structured bool) (PromptPreview, error) {
+	prompt, err := BuildPrompt(commits, promptType, info)
+	if err != nil {
+		return PromptPreview{}, err
+	}
+
+	preview := PromptPreview{
+		Prompt:    prompt,
+		Tokens:    EstimateTokens(prompt),
+		MaxTokens: cfg.maxPromptTokensOrDefault(DefaultMaxPromptTokens(provider)),
+	}
+	preview.Chunks = len(planChunks(commits, preview.Tokens, cfg.ChunkStrategy, preview.MaxTokens, info.Language))
+	if structured {
+		preview.Prompt = withStructuredInstruction(prompt, info.Language)
+	}
+	return preview, nil
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// fakeSummarizer 用于测试的Summarizer实现，不访问网络
+type fakeSummarizer struct {
+	summary string
+	closed  bool
+}
+
+func (f *fakeSummarizer) SummarizeCommitsWithPrompt(_ context.Context, _ []git.CommitInfo, _ PromptType, _ PromptInfo) (string, error) {
+	return f.summary, nil
+}
+
+func (f *fakeSummarizer) Close() {
+	f.closed = true
+}
+
+// TestNewSummarizer 测试提供方注册和创建
+func TestNewSummarizer(t *testing.T) {
+	fake := &fakeSummarizer{summary: "测试摘要"}
+	RegisterProvider("Fake", func(cfg Config) (Summarizer, error) {
+		if cfg.ModelName != "test-model" {
+			t.Errorf("模型名称应为 'test-model', 得到: %s", cfg.ModelName)
+		}
+		return fake, nil
+	})
+	defer delete(providers, "fake")
+
+	// 提供方名称不区分大小写
+	s, err := NewSummarizer("FAKE", Config{ModelName: "test-model"})
+	if err != nil {
+		t.Fatalf("创建Summarizer失败: %v", err)
+	}
+
+	summary, err := s.SummarizeCommitsWithPrompt(context.Background(), nil, BasicPrompt, PromptInfo{})
+	if err != nil || summary != "测试摘要" {
+		t.Errorf("摘要应为 '测试摘要', 得到: %q (err: %v)", summary, err)
+	}
+
+	s.Close()
+	if !fake.closed {
+		t.Error("Close应该被调用")
+	}
+
+	// 未知的提供方应该返回错误
+	if _, err := NewSummarizer("unknown", Config{}); err == nil {
+		t.Error("未知的提供方应该返回错误")
+	}
+
+	// 内置的Gemini提供方应该已经注册
+	if !contains(Providers(), ProviderGemini) {
+		t.Errorf("提供方列表应包含 %s, 得到: %v", ProviderGemini, Providers())
+	}
+}
+
+// contains 检查切片是否包含指定元素
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// TestBuildPrompt 测试构建的提示词包含提交记录
+func TestBuildPrompt(t *testing.T) {
+	prompt, err := BuildPrompt(testCommits(), BasicPrompt, PromptInfo{})
+	if err != nil {
+		t.Fatalf("构建提示词失败: %v", err)
+	}
+	if !strings.Contains(prompt, "feat: 添加周报功能") || strings.Contains(prompt, "{{.CommitMessages}}") {
+		t.Errorf("提示词应包含提交消息且不包含占位符, 得到:\n%s", prompt)
+	}
+}
+
+// TestPreviewPrompt 测试预览的提示词与生成报告时一致：使用提供方默认的token上限，结构化模式追加JSON格式说明
+func TestPreviewPrompt(t *testing.T) {
+	preview, err := PreviewPrompt(testCommits(), BasicPrompt, PromptInfo{}, ProviderOllama, Config{}, true)
+	if err != nil {
+		t.Fatalf("预览提示词失败: %v", err)
+	}
+	if preview.MaxTokens != DefaultOllamaMaxPromptTokens || preview.Chunks != 0 {
+		t.Errorf("应使用Ollama默认的token上限且不分段, 得到: %+v", preview)
+	}
+	if !strings.Contains(preview.Prompt, "feat: 添加周报功能") || !strings.HasSuffix(preview.Prompt, structuredInstruction) {
+		t.Errorf("结构化模式的提示词应包含JSON格式说明, 得到:\n%s", preview.Prompt)
+	}
+
+	// 超出上限时给出分段数量
+	commits := make([]git.CommitInfo, 0, 200)
+	for i := 0; i < 200; i++ {
+		commits = append(commits, testCommits()[0])
+	}
+	preview, err = PreviewPrompt(commits, BasicPrompt, PromptInfo{}, ProviderGemini, Config{MaxPromptTokens: 2000}, false)
+	if err != nil {
+		t.Fatalf("预览提示词失败: %v", err)
+	}
+	if preview.MaxTokens != 2000 || preview.Chunks < 2 || strings.Contains(preview.Prompt, "JSON") {
+		t.Errorf("超出上限时应分段且不包含JSON格式说明, 得到: 上限 %d, %d 段", preview.MaxTokens, preview.Chunks)
+	}
+	if DefaultMaxPromptTokens("") != DefaultGeminiMaxPromptTokens || DefaultMaxPromptTokens(ProviderHeuristic) != 0 {
+		t.Error("提供方默认的token上限不正确")
+	}
+}
@@ -0,0 +1,155 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// PromptInfo 报告的元信息，与提交记录一起用于渲染提示词模板
+type PromptInfo struct {
+	From     time.Time     // 报告开始时间，为零值时使用最早的提交时间
+	To       time.Time     // 报告结束时间，为零值时使用最晚的提交时间
+	Author   string        // 筛选的作者，为空表示所有作者
+	Language i18n.Language // 报告语言，为空时使用中文
+}
+
+// PromptData 是提示词模板的数据对象，提示词文件通过text/template渲染，
+// 例如 {{.ReportType}}、{{range .Commits}}{{.Message}}{{end}}、{{if .Author}}...{{end}}
+type PromptData struct {
+	CommitMessages string           // 格式化后的提交记录文本
+	Commits        []git.CommitInfo // 提交记录，分段总结的最终合并步骤中为空
+	CommitCount    int              // 提交数量
+	From           time.Time        // 开始时间
+	To             time.Time        // 结束时间
+	FromDate       string           // 开始日期，YYYY-MM-DD格式
+	ToDate         string           // 结束日期，YYYY-MM-DD格式
+	Author         string           // 筛选的作者，为空表示所有作者
+	Repos          []string         // 涉及的仓库，按名称排序
+	RepoCounts     map[string]int   // 每个仓库的提交数量
+	ReportType     string           // 报告类型：日报、周报、月报、年报或工作报告
+	Language       i18n.Language    // 报告语言，如zh或en
+	ChunkLabel     string           // 分段总结时当前分段的名称
+}
+
+// newPromptData 根据提交记录和报告元信息创建模板数据
+func newPromptData(commits []git.CommitInfo, info PromptInfo) PromptData {
+	from, to := info.From, info.To
+	if from.IsZero() || to.IsZero() {
+		earliest, latest := commitDateRange(commits)
+		if from.IsZero() {
+			from = earliest
+		}
+		if to.IsZero() {
+			to = latest
+		}
+	}
+
+	language := info.Language
+	if language == "" {
+		language = i18n.Chinese
+	}
+
+	repoCounts := make(map[string]int)
+	for _, commit := range commits {
+		if commit.RepoPath != "" {
+			repoCounts[commit.RepoPath]++
+		}
+	}
+
+	return PromptData{
+		CommitMessages: formatCommitMessages(commits, language),
+		Commits:        commits,
+		CommitCount:    len(commits),
+		From:           from,
+		To:             to,
+		FromDate:       from.Format("2006-01-02"),
+		ToDate:         to.Format("2006-01-02"),
+		Author:         info.Author,
+		Repos:          sortedKeys(repoCounts),
+		RepoCounts:     repoCounts,
+		ReportType:     i18n.Translate(language, reportTypeLabel(from, to)),
+		Language:       language,
+	}
+}
+
+// commitDateRange 返回提交记录中最早和最晚的提交时间
+func commitDateRange(commits []git.CommitInfo) (time.Time, time.Time) {
+	var earliest, latest time.Time
+	for i, commit := range commits {
+		if i == 0 || commit.Date.Before(earliest) {
+			earliest = commit.Date
+		}
+		if i == 0 || commit.Date.After(latest) {
+			latest = commit.Date
+		}
+	}
+	return earliest, latest
+}
+
+// reportTypeLabel 根据时间范围返回报告类型
+func reportTypeLabel(from, to time.Time) string {
+	daysDiff := to.Sub(from).Hours() / 24
+
+	switch {
+	case daysDiff <= 1:
+		return "日报"
+	case daysDiff <= 7:
+		return "周报"
+	case daysDiff <= 31:
+		return "月报"
+	case daysDiff <= 366:
+		return "年报"
+	default:
+		return "工作报告"
+	}
+}
+
+// localized 根据语言选择中文或英文文本，用于内置的提示词片段
+func localized(lang i18n.Language, zh, en string) string {
+	if lang == i18n.English {
+		return en
+	}
+	return zh
+}
+
+// promptFuncs 提示词模板中可用的函数
+var promptFuncs = template.FuncMap{
+	"join":      strings.Join,
+	"shortHash": shortHash,
+}
+
+// shortHash 返回提交哈希的前8位
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// parsePromptTemplate 解析提示词模板，引用不存在的字段时执行会报错
+func parsePromptTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析提示词模板失败: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderPromptTemplate 使用text/template将数据填入提示词模板
+func renderPromptTemplate(text string, data PromptData) (string, error) {
+	tmpl, err := parsePromptTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("渲染提示词模板失败: %w", err)
+	}
+	return result.String(), nil
+}
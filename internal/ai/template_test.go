@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// TestRenderPromptTemplate 测试提示词模板可以使用报告元信息、循环和条件
+func TestRenderPromptTemplate(t *testing.T) {
+	commits := []git.CommitInfo{
+		{Hash: "abcdef1234567890", Date: time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC), Message: "feat: 添加周报", RepoPath: "web"},
+		{Hash: "1234567890abcdef", Date: time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), Message: "fix: 修复导出", RepoPath: "api"},
+		{Hash: "fedcba0987654321", Date: time.Date(2025, 5, 22, 10, 0, 0, 0, time.UTC), Message: "docs: 更新文档", RepoPath: "api"},
+	}
+	info := PromptInfo{
+		From:   time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+		Author: "张三",
+	}
+
+	template := `{{.ReportType}} {{.FromDate}}~{{.ToDate}} {{if .Author}}作者:{{.Author}}{{end}} 语言:{{.Language}}
+仓库: {{join .Repos ","}}
+{{range $repo, $count := .RepoCounts}}{{$repo}}={{$count}};{{end}}
+{{range .Commits}}{{shortHash .Hash}} {{.Message}}
+{{end}}`
+	prompt, err := renderPromptTemplate(template, newPromptData(commits, info))
+	if err != nil {
+		t.Fatalf("渲染失败: %v", err)
+	}
+
+	expected := []string{"周报 2025-05-19~2025-05-25 作者:张三 语言:zh", "仓库: api,web", "api=2;web=1;", "abcdef12 feat: 添加周报"}
+	for _, s := range expected {
+		if !strings.Contains(prompt, s) {
+			t.Errorf("提示词应包含 %q, 得到:\n%s", s, prompt)
+		}
+	}
+
+	// 未指定时间范围时使用提交记录的时间范围
+	data := newPromptData(commits, PromptInfo{})
+	if data.FromDate != "2025-05-20" || data.ToDate != "2025-05-22" || data.ReportType != "周报" {
+		t.Errorf("时间范围不正确: %s ~ %s (%s)", data.FromDate, data.ToDate, data.ReportType)
+	}
+
+	// 引用不存在的字段和语法错误应返回错误
+	for _, invalid := range []string{"{{.Unknown}}", "{{if .Author}}"} {
+		if _, err := renderPromptTemplate(invalid, data); err == nil {
+			t.Errorf("应该返回错误: %s", invalid)
+		}
+	}
+}
+
+// TestBuildPromptWithInfo 测试自定义提示词文件使用报告元信息渲染
+func TestBuildPromptWithInfo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kpi.md")
+	if err := os.WriteFile(path, []byte("{{.Author}}的{{.ReportType}}，共{{.CommitCount}}条提交\n{{range .Commits}}- {{.Message}}\n{{end}}"), 0o644); err != nil {
+		t.Fatalf("写入提示词文件失败: %v", err)
+	}
+
+	info := PromptInfo{
+		From:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+		Author: "张三",
+	}
+	prompt, err := BuildPrompt(testCommits(), PromptType(path), info)
+	if err != nil {
+		t.Fatalf("构建提示词失败: %v", err)
+	}
+
+	// 模板已经通过.Commits引用了提交记录，不应再在末尾追加
+	if !strings.HasPrefix(prompt, "张三的月报，共1条提交\n- feat: 添加周报功能") || strings.Contains(prompt, "提交记录：") {
+		t.Errorf("提示词不正确:\n%s", prompt)
+	}
+}
+
+// TestBuildPromptEnglish 测试英文报告使用英文的内置提示词和提交记录标签
+func TestBuildPromptEnglish(t *testing.T) {
+	prompt, err := BuildPrompt(testCommits(), DetailedPrompt, PromptInfo{Language: i18n.English})
+	if err != nil {
+		t.Fatalf("构建提示词失败: %v", err)
+	}
+
+	for _, s := range []string{"in English", "Commit 1:", "- Message: feat: 添加周报功能", "## Next Steps"} {
+		if !strings.Contains(prompt, s) {
+			t.Errorf("英文提示词应包含 %q, 得到:\n%s", s, prompt)
+		}
+	}
+	if strings.Contains(prompt, "提交记录") {
+		t.Errorf("英文提示词不应包含中文标签:\n%s", prompt)
+	}
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ExecRepository 通过执行系统中安装的git命令读取仓库
+type ExecRepository struct {
+	Path string // 仓库路径，git命令的工作目录
+}
+
+// 确保ExecRepository实现了Repository接口
+var _ Repository = (*ExecRepository)(nil)
+
+// NewExecRepository 创建使用git命令的仓库
+func NewExecRepository(path string) *ExecRepository {
+	return &ExecRepository{Path: path}
+}
+
+// numstatArgs 获取每个文件的新增和删除行数的git log参数
+var numstatArgs = []string{
+	"--numstat", // 同时获取每个文件的新增和删除行数
+	"-z",        // 文件统计以NUL分隔，路径不加引号转义，重命名的原路径和新路径分开输出
+	"-M",        // 识别重命名的文件
+}
+
+// CommitsBetween 使用git log获取所有分支在指定时间范围内的提交
+func (r *ExecRepository) CommitsBetween(ctx context.Context, fromDate, toDate time.Time, opts *Options) ([]CommitInfo, error) {
+	// git log --author只匹配作者，不匹配Co-authored-by尾注中的共同作者，因此解析后再按作者筛选
+	matchAuthor, err := authorMatcher(opts.authors())
+	if err != nil {
+		return nil, err
+	}
+
+	// 构建git log命令的参数列表
+	args := []string{
+		"log",
+		"--all", // 获取所有分支的提交
+		"--pretty=format:" + logFormat,
+		"--date=iso-strict", // RFC3339格式的日期
+		"--decorate=full",   // 输出完整的引用名称，区分本地分支和远程分支
+		// 传入带时区的完整时间，不丢失时分秒。git按提交日期筛选，
+		// 提交日期不早于作者日期，因此按作者日期筛选时也可以用它排除更早的提交
+		"--since=" + fromDate.Format(time.RFC3339),
+	}
+
+	// rebase或cherry-pick过的提交，提交日期可能晚于范围结束时间，按作者日期筛选时不能用--until排除
+	field := opts.dateField()
+	if field == DateFieldCommitter {
+		args = append(args, "--until="+toDate.Format(time.RFC3339))
+	}
+
+	// 不筛选作者时一次获取提交和文件统计
+	if matchAuthor == nil {
+		commits, err := r.log(ctx, append(args, numstatArgs...), "")
+		if err != nil {
+			return nil, err
+		}
+		return selectByDate(commits, fromDate, toDate, field), nil
+	}
+
+	// 计算差异和识别重命名的开销较大，先只读取提交信息并按作者筛选，
+	// 再只为匹配的提交获取文件统计，避免在多人共用的仓库中为其他作者的提交计算差异
+	commits, err := r.log(ctx, args, "")
+	if err != nil {
+		return nil, err
+	}
+	commits = selectByDate(filterByAuthors(commits, matchAuthor), fromDate, toDate, field)
+	if err := r.addFileStats(ctx, commits); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// addFileStats 使用git log --no-walk只为指定的提交获取文件统计，哈希通过标准输入传入，不受命令行长度限制
+func (r *ExecRepository) addFileStats(ctx context.Context, commits []CommitInfo) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	var hashes strings.Builder
+	for _, commit := range commits {
+		hashes.WriteString(commit.Hash + "\n")
+	}
+	args := append([]string{"log", "--no-walk=unsorted", "--stdin", "--pretty=format:" + logFormat, "--date=iso-strict"}, numstatArgs...)
+	withStats, err := r.log(ctx, args, hashes.String())
+	if err != nil {
+		return err
+	}
+
+	stats := make(map[string][]FileStat, len(withStats))
+	for _, commit := range withStats {
+		stats[commit.Hash] = commit.FileStats
+	}
+	for i := range commits {
+		for _, stat := range stats[commits[i].Hash] {
+			commits[i].addFileStat(stat)
+		}
+	}
+	return nil
+}
+
+// log 执行git log并解析输出，stdin不为空时作为命令的标准输入
+func (r *ExecRepository) log(ctx context.Context, args []string, stdin string) ([]CommitInfo, error) {
+	cmd := r.command(ctx, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		// 取消时git进程被终止，返回ctx的错误而不是进程退出的错误
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("执行git log失败: %w", err)
+	}
+	return parseCommits(string(output))
+}
+
+// CommitDetails 使用git show获取指定提交的详细信息
+func (r *ExecRepository) CommitDetails(ctx context.Context, hash string) (*CommitInfo, error) {
+	// 获取提交的基本信息和变更统计
+	args := append([]string{"show", "--pretty=format:" + logFormat, "--date=iso-strict", "--decorate=full"}, numstatArgs...)
+	output, err := r.command(ctx, append(args, hash)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("获取提交详情失败: %w", err)
+	}
+
+	// 解析提交信息
+	commits, err := parseCommits(string(output))
+	if err != nil || len(commits) == 0 {
+		return nil, fmt.Errorf("解析提交详情失败: %w", err)
+	}
+
+	return &commits[0], nil
+}
+
+// UserName 使用git config获取仓库配置的用户名，仓库中没有配置时使用全局配置
+func (r *ExecRepository) UserName() (string, error) {
+	name, err := r.config("user.name")
+	if err != nil {
+		return "", fmt.Errorf("获取Git用户名失败: %w", err)
+	}
+	return name, nil
+}
+
+// UserEmail 使用git config获取仓库配置的邮箱，仓库中没有配置时使用全局配置
+func (r *ExecRepository) UserEmail() (string, error) {
+	email, err := r.config("user.email")
+	if err != nil {
+		return "", fmt.Errorf("获取Git邮箱失败: %w", err)
+	}
+	return email, nil
+}
+
+// config 读取git配置项，仓库中读取失败时读取全局配置
+func (r *ExecRepository) config(key string) (string, error) {
+	// 执行命令
+	output, err := r.command(context.Background(), "config", key).Output()
+	if err != nil {
+		// 如果获取失败，尝试获取全局配置
+		output, err = exec.Command("git", "config", "--global", key).Output()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// 去除空白字符
+	return strings.TrimSpace(string(output)), nil
+}
+
+// command 创建在仓库目录中执行的git命令，ctx取消时终止git进程
+func (r *ExecRepository) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if r.Path != "" {
+		cmd.Dir = r.Path
+	}
+	return cmd
+}
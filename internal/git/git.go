@@ -1,21 +1,27 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/kway-teow/git-work-log/internal/i18n"
 )
 
 // Options Git操作的选项
 type Options struct {
-	RepoPath string // Git仓库路径
-	Author   string // 作者名称，用于筛选提交
+	RepoPath  string    // Git仓库路径
+	Authors   []string  // 用于筛选提交的作者名称、邮箱或正则表达式，匹配任意一个即可，为空时不筛选
+	DateField DateField // 筛选、排序和显示提交时使用的日期，默认为作者日期
 }
 
-// NewGitOptions 创建新的Git选项
+// NewGitOptions 创建新的Git选项，默认筛选当前用户的提交
 func NewGitOptions(repoPath string) *Options {
 	// 如果没有指定路径，使用当前目录
 	if repoPath == "" {
@@ -27,67 +33,221 @@ func NewGitOptions(repoPath string) *Options {
 		RepoPath: repoPath,
 	}
 
-	// 获取当前用户的Git用户名
-	author, err := GetGitUserName(repoPath)
-	if err == nil && author != "" {
-		opts.Author = author
+	// 获取当前用户的Git用户名和邮箱
+	if repo, err := Open(repoPath); err == nil {
+		opts.Authors = userIdentities(repo)
 	}
 
 	return opts
 }
 
+// userIdentities 返回仓库配置的用户名和邮箱，转义为按字面匹配的正则表达式。
+// 同时匹配邮箱，用其他名称但相同邮箱提交的记录也属于当前用户
+func userIdentities(repo Repository) []string {
+	var identities []string
+	if name, err := repo.UserName(); err == nil && name != "" {
+		identities = append(identities, regexp.QuoteMeta(name))
+	}
+	if email, err := repo.UserEmail(); err == nil && email != "" {
+		identities = append(identities, regexp.QuoteMeta(email))
+	}
+	return identities
+}
+
+// DateField 提交的日期字段
+type DateField string
+
+const (
+	// DateFieldAuthor 作者日期，即最初编写提交的时间，rebase和cherry-pick后保持不变
+	DateFieldAuthor DateField = "author"
+	// DateFieldCommitter 提交日期，即提交最后一次被创建的时间，rebase和cherry-pick后会更新
+	DateFieldCommitter DateField = "committer"
+)
+
+// DateFields 返回所有支持的日期字段名称
+func DateFields() []string {
+	return []string{string(DateFieldAuthor), string(DateFieldCommitter)}
+}
+
+// ParseDateField 解析日期字段名称，为空时使用作者日期
+func ParseDateField(name string) (DateField, error) {
+	switch field := DateField(strings.ToLower(strings.TrimSpace(name))); field {
+	case "":
+		return DateFieldAuthor, nil
+	case DateFieldAuthor, DateFieldCommitter:
+		return field, nil
+	default:
+		return "", fmt.Errorf("不支持的日期字段: %s (可选 %s)", name, strings.Join(DateFields(), ", "))
+	}
+}
+
+const (
+	// recordSeparator 每条提交记录开头的记录分隔符
+	recordSeparator = "\x1e"
+	// fieldSeparator 字段之间的分隔符，Git不允许提交消息中包含NUL
+	fieldSeparator = "\x00"
+	// logFieldCount logFormat中以NUL结尾的字段数量
+	logFieldCount = 7
+	// logFormat git log的输出格式：提交哈希、作者名称和邮箱（按.mailmap映射）、作者日期、提交日期、
+	// 引用名称（用于获取分支信息）和完整的提交消息
+	logFormat = "%x1e%H%x00%aN%x00%aE%x00%ad%x00%cd%x00%D%x00%B%x00"
+)
+
 // CommitInfo 表示一个Git提交的信息
 type CommitInfo struct {
-	Hash         string
-	Author       string
-	Date         time.Time
-	Message      string
-	Branches     []string // 分支信息
-	ChangedFiles []string
-	RepoPath     string // 仓库路径，标识提交来自哪个仓库
+	Hash          string     `json:"hash"`
+	Author        string     `json:"author"`
+	AuthorEmail   string     `json:"author_email"`
+	Date          time.Time  `json:"date"`           // 筛选、排序和显示使用的日期，由Options.DateField选择
+	AuthorDate    time.Time  `json:"author_date"`    // 作者日期
+	CommitterDate time.Time  `json:"committer_date"` // 提交日期，rebase和cherry-pick后与作者日期不同
+	Message       string     `json:"message"`        // 提交消息的标题（第一行）
+	Body          string     `json:"body"`           // 提交消息的正文，没有正文时为空
+	Branches      []string   `json:"branches"`       // 分支信息
+	ChangedFiles  []string   `json:"changed_files"`  // 变更的文件，重命名的文件为新路径
+	FileStats     []FileStat `json:"file_stats"`     // 每个文件的变更统计，与ChangedFiles顺序一致
+	Insertions    int        `json:"insertions"`     // 新增的行数
+	Deletions     int        `json:"deletions"`      // 删除的行数
+	CoAuthors     []CoAuthor `json:"co_authors"`     // 提交消息中Co-authored-by尾注声明的共同作者
+	Role          CommitRole `json:"role,omitempty"` // 按作者筛选时，匹配的身份在提交中的角色
+	RepoPath      string     `json:"repo"`           // 仓库路径，标识提交来自哪个仓库
+}
+
+// CoAuthor 提交消息中Co-authored-by尾注声明的共同作者，如结对编程时在同事电脑上的提交
+type CoAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CommitRole 筛选的作者在提交中的角色
+type CommitRole string
+
+const (
+	// RoleAuthor 提交的作者
+	RoleAuthor CommitRole = "author"
+	// RoleCoAuthor Co-authored-by尾注中的共同作者
+	RoleCoAuthor CommitRole = "co-author"
+)
+
+// coAuthorPattern 匹配提交消息中的Co-authored-by尾注，如 Co-authored-by: Name <email>
+var coAuthorPattern = regexp.MustCompile(`(?im)^co-authored-by:[ \t]*([^<\n]*?)[ \t]*<([^<>\n]*)>[ \t]*$`)
+
+// FileStat 单个文件的变更统计，来自git log --numstat
+type FileStat struct {
+	Path       string `json:"path"`               // 文件路径
+	OldPath    string `json:"old_path,omitempty"` // 重命名前的路径，没有重命名时为空
+	Insertions int    `json:"insertions"`         // 新增的行数
+	Deletions  int    `json:"deletions"`          // 删除的行数
+	Binary     bool   `json:"binary,omitempty"`   // 是否为二进制文件，二进制文件没有行数统计
+}
+
+// Renames 返回提交中重命名的文件
+func (c CommitInfo) Renames() []FileStat {
+	var renames []FileStat
+	for _, stat := range c.FileStats {
+		if stat.OldPath != "" {
+			renames = append(renames, stat)
+		}
+	}
+	return renames
 }
 
 // GetCommitsBetween 获取指定时间范围内的所有提交
-func GetCommitsBetween(fromDate, toDate time.Time, opts *Options) ([]CommitInfo, error) {
-	// 格式化日期为git log可接受的格式
-	fromStr := fromDate.Format("2006-01-02")
-	toStr := toDate.Format("2006-01-02")
+func GetCommitsBetween(ctx context.Context, fromDate, toDate time.Time, opts *Options) ([]CommitInfo, error) {
+	repo, err := Open(opts.repoPath())
+	if err != nil {
+		return nil, err
+	}
 
-	// 构建git log命令的参数列表
-	args := []string{
-		"log",
-		"--all",                            // 获取所有分支的提交
-		"--pretty=format:%H|%an|%ad|%s|%D", // 添加%D获取分支信息
-		"--date=iso",
-		"--after=" + fromStr,
-		"--before=" + toStr,
+	return repo.CommitsBetween(ctx, fromDate, toDate, opts)
+}
+
+// selectByDate 将Date设置为field选择的日期，返回该日期在 [fromDate, toDate] 范围内的提交，
+// 按该日期倒序排列。两个后端都先按提交日期粗略筛选，再由这里精确筛选
+func selectByDate(commits []CommitInfo, fromDate, toDate time.Time, field DateField) []CommitInfo {
+	for i := range commits {
+		commits[i].Date = commits[i].AuthorDate
+		if field == DateFieldCommitter {
+			commits[i].Date = commits[i].CommitterDate
+		}
 	}
 
-	// 如果指定了作者，添加作者筛选条件
-	if opts != nil && opts.Author != "" {
-		args = append(args, "--author="+opts.Author)
+	filtered := filterByDate(commits, fromDate, toDate)
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Date.After(filtered[j].Date) })
+	return filtered
+}
+
+// authorMatcher 返回与git log --author相同的作者匹配函数：
+// 按正则表达式匹配 "作者名 <邮箱>"，匹配任意一个即可，没有条件时返回nil表示不筛选
+func authorMatcher(patterns []string) (func(name, email string) bool, error) {
+	if len(patterns) == 0 {
+		return nil, nil
 	}
 
-	// 构建git log命令
-	cmd := exec.Command("git", args...)
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("作者筛选条件 %s 不是有效的正则表达式: %w", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return func(name, email string) bool {
+		identity := name + " <" + email + ">"
+		for _, re := range regexps {
+			if re.MatchString(identity) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
 
-	// 设置工作目录
-	if opts != nil {
-		cmd.Dir = opts.RepoPath
+// matchRole 返回筛选条件匹配的身份在提交中的角色，作者优先于共同作者，都不匹配时返回false。
+// match为nil表示不筛选，所有提交都匹配且没有角色
+func matchRole(match func(name, email string) bool, name, email string, coAuthors []CoAuthor) (CommitRole, bool) {
+	if match == nil {
+		return "", true
+	}
+	if match(name, email) {
+		return RoleAuthor, true
+	}
+	for _, coAuthor := range coAuthors {
+		if match(coAuthor.Name, coAuthor.Email) {
+			return RoleCoAuthor, true
+		}
 	}
+	return "", false
+}
 
-	// 执行命令
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("执行git log失败: %w", err)
+// filterByAuthors 返回作者或共同作者匹配的提交，并记录匹配的角色
+func filterByAuthors(commits []CommitInfo, match func(name, email string) bool) []CommitInfo {
+	filtered := make([]CommitInfo, 0, len(commits))
+	for _, commit := range commits {
+		role, ok := matchRole(match, commit.Author, commit.AuthorEmail, commit.CoAuthors)
+		if !ok {
+			continue
+		}
+		commit.Role = role
+		filtered = append(filtered, commit)
 	}
+	return filtered
+}
 
-	// 解析输出
-	return parseCommits(string(output))
+// filterByDate 返回日期在 [fromDate, toDate] 范围内的提交，两端都包含
+func filterByDate(commits []CommitInfo, fromDate, toDate time.Time) []CommitInfo {
+	filtered := make([]CommitInfo, 0, len(commits))
+	for _, commit := range commits {
+		if commit.Date.Before(fromDate) || commit.Date.After(toDate) {
+			continue
+		}
+		filtered = append(filtered, commit)
+	}
+	return filtered
 }
 
 // GetCommitsThisWeek 获取本周的所有提交
-func GetCommitsThisWeek(opts *Options) ([]CommitInfo, error) {
+func GetCommitsThisWeek(ctx context.Context, opts *Options) ([]CommitInfo, error) {
 	// 计算本周一和下周一的日期
 	now := time.Now()
 	weekday := int(now.Weekday())
@@ -102,156 +262,226 @@ func GetCommitsThisWeek(opts *Options) ([]CommitInfo, error) {
 	// 计算下周一的日期
 	nextMonday := monday.AddDate(0, 0, 7)
 
-	return GetCommitsBetween(monday, nextMonday, opts)
+	return GetCommitsBetween(ctx, monday, nextMonday, opts)
 }
 
 // GetCommitDetails 获取指定提交的详细信息
-func GetCommitDetails(hash string, opts *Options) (*CommitInfo, error) {
-	// 获取提交的基本信息
-	cmd := exec.Command("git", "show",
-		"--pretty=format:%H|%an|%ad|%s|%D",
-		"--date=iso",
-		hash)
-
-	// 设置工作目录
-	if opts != nil {
-		cmd.Dir = opts.RepoPath
+func GetCommitDetails(ctx context.Context, hash string, opts *Options) (*CommitInfo, error) {
+	repo, err := Open(opts.repoPath())
+	if err != nil {
+		return nil, err
 	}
+	return repo.CommitDetails(ctx, hash)
+}
 
-	output, err := cmd.Output()
+// GetGitUserName 获取Git用户名
+func GetGitUserName(repoPath string) (string, error) {
+	repo, err := Open(repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("获取提交详情失败: %w", err)
+		return "", err
 	}
+	return repo.UserName()
+}
 
-	// 解析提交信息
-	commits, err := parseCommits(string(output))
-	if err != nil || len(commits) == 0 {
-		return nil, fmt.Errorf("解析提交详情失败: %w", err)
+// repoPath 返回选项中的仓库路径，没有指定时使用当前目录
+func (o *Options) repoPath() string {
+	if o == nil || o.RepoPath == "" {
+		return "."
 	}
+	return o.RepoPath
+}
 
-	commit := commits[0]
-
-	// 获取变更的文件列表
-	cmdFiles := exec.Command("git", "show", "--name-only", "--pretty=format:", hash)
-
-	// 设置工作目录
-	if opts != nil {
-		cmdFiles.Dir = opts.RepoPath
+// authors 返回选项中用于筛选的作者，没有指定时为空
+func (o *Options) authors() []string {
+	if o == nil {
+		return nil
 	}
+	return o.Authors
+}
 
-	outputFiles, err := cmdFiles.Output()
-	if err != nil {
-		return nil, fmt.Errorf("获取变更文件列表失败: %w", err)
+// dateField 返回选项中的日期字段，没有指定时使用作者日期
+func (o *Options) dateField() DateField {
+	if o == nil || o.DateField == "" {
+		return DateFieldAuthor
 	}
-
-	// 解析变更文件列表
-	files := strings.Split(strings.TrimSpace(string(outputFiles)), "\n")
-	commit.ChangedFiles = files
-
-	return &commit, nil
+	return o.DateField
 }
 
-// GetGitUserName 获取Git用户名
-func GetGitUserName(repoPath string) (string, error) {
-	// 构建git config命令获取用户名
-	cmd := exec.Command("git", "config", "user.name")
+// parseCommits 解析git log的输出，输出格式见logFormat：
+// 每条记录以记录分隔符开头，前7个字段以NUL结尾，之后是--numstat -z的文件统计。
+// 提交消息中不会出现NUL，因此任意的消息内容（包括|和换行）都不会影响字段的划分
+func parseCommits(output string) ([]CommitInfo, error) {
+	commits := make([]CommitInfo, 0, strings.Count(output, recordSeparator))
 
-	// 设置工作目录
-	if repoPath != "" {
-		cmd.Dir = repoPath
-	}
+	for {
+		start := strings.Index(output, recordSeparator)
+		if start < 0 {
+			break
+		}
+		output = output[start+len(recordSeparator):]
+
+		// 按NUL读取固定数量的字段，消息中即使包含记录分隔符也不会被截断
+		fields := make([]string, 0, logFieldCount)
+		for len(fields) < logFieldCount {
+			end := strings.Index(output, fieldSeparator)
+			if end < 0 {
+				return nil, fmt.Errorf("提交记录不完整: 缺少第 %d 个字段", len(fields)+1)
+			}
+			fields = append(fields, output[:end])
+			output = output[end+len(fieldSeparator):]
+		}
 
-	// 执行命令
-	output, err := cmd.Output()
-	if err != nil {
-		// 如果获取失败，尝试获取全局用户名
-		cmdGlobal := exec.Command("git", "config", "--global", "user.name")
-		output, err = cmdGlobal.Output()
+		commit, err := parseCommitFields(fields)
 		if err != nil {
-			return "", fmt.Errorf("获取Git用户名失败: %w", err)
+			return nil, err
+		}
+
+		// 到下一条记录之前都是文件统计
+		end := strings.Index(output, recordSeparator)
+		if end < 0 {
+			end = len(output)
 		}
+		for _, stat := range parseNumstat(output[:end]) {
+			commit.addFileStat(stat)
+		}
+		output = output[end:]
+
+		commits = append(commits, commit)
 	}
 
-	// 去除空白字符
-	return strings.TrimSpace(string(output)), nil
+	return commits, nil
 }
 
-// parseCommits 解析git log的输出
-func parseCommits(output string) ([]CommitInfo, error) {
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	commits := make([]CommitInfo, 0, len(lines))
+// parseCommitFields 解析一条记录中的提交哈希、作者名称和邮箱、作者日期、提交日期、引用名称和完整的提交消息，
+// Date默认为作者日期
+func parseCommitFields(fields []string) (CommitInfo, error) {
+	hash, author, email, authorDateStr, committerDateStr, refNames, rawMessage := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6]
 
-	for _, line := range lines {
-		if line == "" {
+	// 解析日期
+	authorDate, err := time.Parse(time.RFC3339, authorDateStr)
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("解析日期失败: %w", err)
+	}
+	committerDate, err := time.Parse(time.RFC3339, committerDateStr)
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("解析提交日期失败: %w", err)
+	}
+
+	subject, body := splitMessage(rawMessage)
+	return CommitInfo{
+		Hash:          hash,
+		Author:        author,
+		AuthorEmail:   email,
+		Date:          authorDate,
+		AuthorDate:    authorDate,
+		CommitterDate: committerDate,
+		Message:       subject,
+		Body:          body,
+		Branches:      parseBranches(refNames),
+		CoAuthors:     parseCoAuthors(rawMessage),
+	}, nil
+}
+
+// parseCoAuthors 解析提交消息中的Co-authored-by尾注，尾注的键不区分大小写，同一邮箱只保留第一个
+func parseCoAuthors(rawMessage string) []CoAuthor {
+	var coAuthors []CoAuthor
+	seen := make(map[string]bool)
+	for _, match := range coAuthorPattern.FindAllStringSubmatch(rawMessage, -1) {
+		coAuthor := CoAuthor{Name: strings.TrimSpace(match[1]), Email: strings.TrimSpace(match[2])}
+		key := strings.ToLower(coAuthor.Email)
+		if seen[key] {
 			continue
 		}
+		seen[key] = true
+		coAuthors = append(coAuthors, coAuthor)
+	}
+	return coAuthors
+}
 
-		parts := strings.SplitN(line, "|", 5) // 增加了分支信息字段
-		if len(parts) < 5 {
-			continue
+// splitMessage 将完整的提交消息分为标题（第一行）和正文
+func splitMessage(rawMessage string) (string, string) {
+	subject, body, _ := strings.Cut(strings.TrimSpace(rawMessage), "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body)
+}
+
+// parseBranches 从--decorate=full时%D输出的引用名称
+// （如 HEAD -> refs/heads/main, refs/remotes/origin/main, tag: refs/tags/v1.0.0）中解析分支名称，去重后按字母排序
+func parseBranches(refNames string) []string {
+	names := make(map[string]bool)
+	for _, ref := range strings.Split(refNames, ",") {
+		ref = strings.TrimSpace(ref)
+		// HEAD -> refs/heads/main 中HEAD指向的分支
+		if _, target, ok := strings.Cut(ref, " -> "); ok {
+			ref = target
 		}
+		if branch, ok := branchName(ref); ok {
+			names[branch] = true
+		}
+	}
+	return sortedNames(names)
+}
 
-		hash := parts[0]
-		author := parts[1]
-		dateStr := parts[2]
-		message := parts[3]
-		refNames := parts[4]
+// branchName 将完整的引用名称转换为报告中的分支名称，两种后端都使用它，保证分支名称一致：
+// 本地分支refs/heads/feature/login为feature/login，远程分支refs/remotes/origin/feature/login去掉远程名称后同样为feature/login。
+// 标签、HEAD等不是分支的引用返回false
+func branchName(ref string) (string, bool) {
+	if branch, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		return branch, true
+	}
+	if remote, ok := strings.CutPrefix(ref, "refs/remotes/"); ok {
+		if _, branch, ok := strings.Cut(remote, "/"); ok && branch != "HEAD" {
+			return branch, true
+		}
+	}
+	return "", false
+}
 
-		// 解析日期
-		date, err := time.Parse("2006-01-02 15:04:05 -0700", dateStr)
-		if err != nil {
-			return nil, fmt.Errorf("解析日期失败: %w", err)
+// parseNumstat 解析--numstat -z输出的文件统计：每个文件为 "新增\t删除\t路径\0"，
+// 重命名的文件路径为空，之后是 "原路径\0新路径\0"，二进制文件的行数为"-"。
+// 使用-z时git不会给路径加引号转义，也不会把重命名写成 "old => new"，路径中的任意字符都原样输出
+func parseNumstat(text string) []FileStat {
+	fields := strings.Split(strings.TrimPrefix(text, "\n"), fieldSeparator)
+
+	var stats []FileStat
+	for i := 0; i < len(fields); i++ {
+		// 提交之间以空字段分隔
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
 		}
 
-		// 解析分支信息
-		var branches []string
-		if refNames != "" {
-			// 分割引用名称（如HEAD -> main, origin/main）
-			refs := strings.Split(refNames, ",")
-			for _, ref := range refs {
-				ref = strings.TrimSpace(ref)
-				// 只保留分支名称，去除tag和HEAD指针
-				switch {
-				case strings.Contains(ref, "refs/heads/"):
-					branch := strings.TrimPrefix(ref, "refs/heads/")
-					branches = append(branches, branch)
-				case strings.Contains(ref, "HEAD -> "):
-					branch := strings.TrimPrefix(ref, "HEAD -> ")
-					branches = append(branches, branch)
-				case !strings.Contains(ref, "tag:") && !strings.HasPrefix(ref, "HEAD"):
-					// 去除远程分支前缀
-					if strings.Contains(ref, "/") {
-						parts := strings.SplitN(ref, "/", 2)
-						if len(parts) > 1 {
-							branches = append(branches, parts[1])
-						}
-					} else {
-						branches = append(branches, ref)
-					}
-				}
+		var stat FileStat
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.Binary = true
+		} else {
+			insertions, err1 := strconv.Atoi(parts[0])
+			deletions, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				continue
 			}
+			stat.Insertions, stat.Deletions = insertions, deletions
 		}
 
-		// 去除重复的分支名
-		uniqueBranches := make([]string, 0)
-		branchMap := make(map[string]bool)
-		for _, branch := range branches {
-			if !branchMap[branch] {
-				branchMap[branch] = true
-				uniqueBranches = append(uniqueBranches, branch)
+		stat.Path = parts[2]
+		if stat.Path == "" {
+			// 重命名的文件，原路径和新路径是之后的两个字段
+			if i+2 >= len(fields) {
+				break
 			}
+			stat.OldPath, stat.Path = fields[i+1], fields[i+2]
+			i += 2
 		}
-
-		commits = append(commits, CommitInfo{
-			Hash:     hash,
-			Author:   author,
-			Date:     date,
-			Message:  message,
-			Branches: uniqueBranches,
-		})
+		stats = append(stats, stat)
 	}
+	return stats
+}
 
-	return commits, nil
+// addFileStat 将文件统计加入提交，并累加新增和删除的行数
+func (c *CommitInfo) addFileStat(stat FileStat) {
+	c.FileStats = append(c.FileStats, stat)
+	c.ChangedFiles = append(c.ChangedFiles, stat.Path)
+	c.Insertions += stat.Insertions
+	c.Deletions += stat.Deletions
 }
 
 // DiscoverGitRepos 发现指定目录下的所有Git仓库
@@ -299,7 +529,7 @@ func DiscoverGitRepos(rootPath string) ([]string, error) {
 		"*.log":            true,
 	}
 
-	fmt.Printf("正在扫描目录: %s\n", absRootPath)
+	i18n.Printf("正在扫描目录: %s\n", absRootPath)
 
 	// 遍历目录
 	err = filepath.Walk(absRootPath, func(path string, info os.FileInfo, err error) error {
@@ -314,7 +544,7 @@ func DiscoverGitRepos(rootPath string) ([]string, error) {
 			if info.Name() == ".git" {
 				repoPath := filepath.Dir(path)
 				repos = append(repos, repoPath)
-				fmt.Printf("  发现Git仓库: %s\n", repoPath)
+				i18n.Printf("  发现Git仓库: %s\n", repoPath)
 				// 跳过.git目录的子目录遍历
 				return filepath.SkipDir
 			}
@@ -345,6 +575,6 @@ func DiscoverGitRepos(rootPath string) ([]string, error) {
 		return nil, fmt.Errorf("遍历目录失败: %w", err)
 	}
 
-	fmt.Printf("扫描完成，共发现 %d 个Git仓库\n", len(repos))
+	i18n.Printf("扫描完成，共发现 %d 个Git仓库\n", len(repos))
 	return repos, nil
 }
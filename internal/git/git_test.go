@@ -1,9 +1,16 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestNewGitOptions 测试创建新的Git选项
@@ -25,8 +32,8 @@ func TestNewGitOptions(t *testing.T) {
 // TestParseCommits 测试解析git log输出
 func TestParseCommits(t *testing.T) {
 	// 模拟git log输出
-	testOutput := "abc123|John Doe|2023-01-01 12:00:00 +0800|Initial commit|HEAD -> main, origin/main\n" +
-		"def456|Jane Smith|2023-01-02 13:00:00 +0800|Add feature|refs/heads/feature, tag: v1.0.0"
+	testOutput := logRecord("abc123", "John Doe", "2023-01-01T12:00:00+08:00", "HEAD -> refs/heads/main, refs/remotes/origin/main, refs/remotes/origin/HEAD", "Initial commit\n", "") +
+		logRecord("def456", "Jane Smith", "2023-01-02T13:00:00+08:00", "refs/heads/feature/login, refs/remotes/origin/feature/login, tag: refs/tags/v1.0.0", "Add feature\n", "")
 
 	commits, err := parseCommits(testOutput)
 	if err != nil {
@@ -60,8 +67,323 @@ func TestParseCommits(t *testing.T) {
 	if commits[1].Hash != "def456" {
 		t.Errorf("第二个提交的哈希应为 'def456', 得到: %s", commits[1].Hash)
 	}
-	if !contains(commits[1].Branches, "feature") {
-		t.Errorf("第二个提交应包含 'feature' 分支, 得到: %v", commits[1].Branches)
+	// 包含斜杠的分支名称保持完整，本地和远程的同名分支只保留一个，标签不是分支
+	if len(commits[1].Branches) != 1 || commits[1].Branches[0] != "feature/login" {
+		t.Errorf("第二个提交应只包含 'feature/login' 分支, 得到: %v", commits[1].Branches)
+	}
+}
+
+// TestParseCommitsNumstat 测试解析--numstat输出的文件和行数统计
+func TestParseCommitsNumstat(t *testing.T) {
+	testOutput := logRecord("abc123", "John Doe", "2023-01-01T12:00:00+08:00", "HEAD -> refs/heads/main", "Add report\n",
+		"10\t2\treport.go\x00-\t-\tlogo.png\x003\t1\t\x00internal/old/data.go\x00internal/new/data.go\x00") +
+		logRecord("def456", "Jane Smith", "2023-01-02T13:00:00+08:00", "", "Merge branch\n", "") +
+		logRecord("ghi789", "Jane Smith", "2023-01-03T14:00:00+08:00", "", "Rename\n",
+			"0\t0\t\x00README\x00README.md\x000\t0\ta\tb.go\x001\t0\t\x00文档/旧 => 名.md\x00文档/{新}.md\x00")
+
+	commits, err := parseCommits(testOutput)
+	if err != nil {
+		t.Fatalf("解析提交失败: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("应解析出3个提交, 得到: %d", len(commits))
+	}
+
+	first := commits[0]
+	if first.Insertions != 13 || first.Deletions != 3 {
+		t.Errorf("行数统计应为 +13 -3, 得到: +%d -%d", first.Insertions, first.Deletions)
+	}
+	wantFiles := []string{"report.go", "logo.png", "internal/new/data.go"}
+	if len(first.ChangedFiles) != len(wantFiles) {
+		t.Fatalf("变更文件应为 %v, 得到: %v", wantFiles, first.ChangedFiles)
+	}
+	for i, file := range wantFiles {
+		if first.ChangedFiles[i] != file {
+			t.Errorf("变更文件应为 %v, 得到: %v", wantFiles, first.ChangedFiles)
+		}
+	}
+	if !first.FileStats[1].Binary {
+		t.Error("logo.png应标记为二进制文件")
+	}
+	if renames := first.Renames(); len(renames) != 1 || renames[0].OldPath != "internal/old/data.go" {
+		t.Errorf("重命名统计不正确: %+v", renames)
+	}
+
+	// 合并提交没有文件统计
+	if len(commits[1].ChangedFiles) != 0 {
+		t.Errorf("合并提交不应有变更文件, 得到: %v", commits[1].ChangedFiles)
+	}
+	// -z输出的路径不加引号转义，包含 " => "、大括号和非ASCII字符的路径原样保留
+	renames := commits[2].Renames()
+	if len(renames) != 2 || renames[0].OldPath != "README" || renames[0].Path != "README.md" {
+		t.Errorf("重命名统计不正确: %+v", renames)
+	}
+	if len(renames) == 2 && (renames[1].OldPath != "文档/旧 => 名.md" || renames[1].Path != "文档/{新}.md" || renames[1].Insertions != 1) {
+		t.Errorf("非ASCII路径的重命名统计不正确: %+v", renames[1])
+	}
+	if len(commits[2].ChangedFiles) != 3 || commits[2].ChangedFiles[1] != "a\tb.go" {
+		t.Errorf("包含制表符的路径应原样保留, 得到: %q", commits[2].ChangedFiles)
+	}
+}
+
+// TestParseCommitsMessage 测试包含|、换行和正文的提交消息
+func TestParseCommitsMessage(t *testing.T) {
+	testOutput := logRecord("abc123", "John | Doe", "2023-01-01T12:00:00+08:00", "HEAD -> refs/heads/main",
+		"fix: 处理 a|b 的情况\n\n正文第一段\n\n- 列表 | 项\n", "1\t1\tmain.go\x00") +
+		logRecord("def456", "Jane Smith", "2023-01-02T13:00:00+08:00", "", "只有标题", "")
+
+	commits, err := parseCommits(testOutput)
+	if err != nil {
+		t.Fatalf("解析提交失败: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("应解析出2个提交, 得到: %d", len(commits))
+	}
+
+	first := commits[0]
+	if first.Author != "John | Doe" || first.Message != "fix: 处理 a|b 的情况" {
+		t.Errorf("作者或标题解析错误: %q, %q", first.Author, first.Message)
+	}
+	if first.Body != "正文第一段\n\n- 列表 | 项" {
+		t.Errorf("正文解析错误: %q", first.Body)
+	}
+	if !contains(first.Branches, "main") || len(first.ChangedFiles) != 1 {
+		t.Errorf("分支或文件解析错误: %v, %v", first.Branches, first.ChangedFiles)
+	}
+	if commits[1].Message != "只有标题" || commits[1].Body != "" {
+		t.Errorf("第二个提交解析错误: %+v", commits[1])
+	}
+
+	// 缺少字段的记录应返回错误
+	if _, err := parseCommits(recordSeparator + "abc123" + fieldSeparator + "John"); err == nil {
+		t.Error("不完整的记录应返回错误")
+	}
+}
+
+// TestFilterByDate 测试按日期精确筛选提交，范围两端都包含，并且与时区无关
+func TestFilterByDate(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	from := time.Date(2025, 5, 20, 0, 0, 0, 0, shanghai)
+	to := time.Date(2025, 5, 21, 0, 0, 0, 0, shanghai).Add(-time.Nanosecond)
+
+	commits := []CommitInfo{
+		{Hash: "before", Date: time.Date(2025, 5, 19, 15, 59, 59, 0, time.UTC)}, // 上海时间 5月19日 23:59:59
+		{Hash: "start", Date: time.Date(2025, 5, 19, 16, 0, 0, 0, time.UTC)},    // 上海时间 5月20日 00:00:00
+		{Hash: "end", Date: time.Date(2025, 5, 20, 23, 59, 59, 0, shanghai)},
+		{Hash: "after", Date: time.Date(2025, 5, 21, 0, 0, 0, 0, shanghai)},
+	}
+
+	filtered := filterByDate(commits, from, to)
+	if len(filtered) != 2 || filtered[0].Hash != "start" || filtered[1].Hash != "end" {
+		t.Errorf("应只保留start和end, 得到: %+v", filtered)
+	}
+}
+
+// TestGetCommitsBetweenBoundaries 使用git命令创建仓库，测试时间范围的边界精确到秒
+func TestGetCommitsBetweenBoundaries(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil || os.Getenv("SKIP_GIT_TESTS") == "true" {
+		t.Skip("git命令不可用，跳过测试")
+	}
+
+	dir := t.TempDir()
+	run := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=tester", "GIT_AUTHOR_EMAIL=tester@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=tester", "GIT_COMMITTER_EMAIL=tester@example.com", "GIT_COMMITTER_DATE="+date)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, output)
+		}
+	}
+	run("", "init", "-q")
+	for _, date := range []string{"2025-05-19T23:59:59+08:00", "2025-05-20T00:00:00+08:00", "2025-05-20T23:59:59+08:00", "2025-05-21T00:00:00+08:00"} {
+		run(date, "commit", "-q", "--allow-empty", "-m", date)
+	}
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	from := time.Date(2025, 5, 20, 0, 0, 0, 0, shanghai)
+	to := time.Date(2025, 5, 21, 0, 0, 0, 0, shanghai).Add(-time.Nanosecond)
+	for _, backend := range []Backend{BackendExec, BackendGoGit} {
+		if err := SetBackend(backend); err != nil {
+			t.Fatal(err)
+		}
+		commits, err := GetCommitsBetween(context.Background(), from, to, &Options{RepoPath: dir})
+		if err != nil {
+			t.Fatalf("%s: 获取提交失败: %v", backend, err)
+		}
+		if len(commits) != 2 || commits[0].Message != "2025-05-20T23:59:59+08:00" || commits[1].Message != "2025-05-20T00:00:00+08:00" {
+			t.Errorf("%s: 应只包含5月20日的2个提交, 得到: %+v", backend, commits)
+		}
+
+		// 取消后停止读取并返回context.Canceled
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, authors := range [][]string{nil, {"tester"}} {
+			if _, err := GetCommitsBetween(ctx, from, to, &Options{RepoPath: dir, Authors: authors}); !errors.Is(err, context.Canceled) {
+				t.Errorf("%s: 取消后应返回context.Canceled, 得到: %v", backend, err)
+			}
+		}
+	}
+	_ = SetBackend(BackendExec)
+}
+
+// TestGetCommitsBetweenDateField 测试按作者日期或提交日期筛选、排序和显示，
+// 模拟rebase后提交日期晚于作者日期的提交
+func TestGetCommitsBetweenDateField(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil || os.Getenv("SKIP_GIT_TESTS") == "true" {
+		t.Skip("git命令不可用，跳过测试")
+	}
+
+	dir := t.TempDir()
+	run := func(authorDate, committerDate string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=tester", "GIT_AUTHOR_EMAIL=tester@example.com", "GIT_AUTHOR_DATE="+authorDate,
+			"GIT_COMMITTER_NAME=tester", "GIT_COMMITTER_EMAIL=tester@example.com", "GIT_COMMITTER_DATE="+committerDate)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, output)
+		}
+	}
+	run("", "", "init", "-q")
+	// 上周编写、本周rebase的提交
+	run("2025-05-14T10:00:00Z", "2025-05-20T09:00:00Z", "commit", "-q", "--allow-empty", "-m", "rebased")
+	// 本周编写、下周一才rebase的提交
+	run("2025-05-21T10:00:00Z", "2025-05-26T09:00:00Z", "commit", "-q", "--allow-empty", "-m", "late")
+
+	from := time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+	tests := []struct {
+		field DateField
+		want  string
+		date  time.Time
+	}{
+		{DateFieldAuthor, "late", time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC)},
+		{DateFieldCommitter, "rebased", time.Date(2025, 5, 20, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, backend := range []Backend{BackendExec, BackendGoGit} {
+		if err := SetBackend(backend); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			commits, err := GetCommitsBetween(context.Background(), from, to, &Options{RepoPath: dir, DateField: tt.field})
+			if err != nil {
+				t.Fatalf("%s/%s: 获取提交失败: %v", backend, tt.field, err)
+			}
+			if len(commits) != 1 || commits[0].Message != tt.want || !commits[0].Date.Equal(tt.date) {
+				t.Errorf("%s/%s: 应只包含 %s (%s), 得到: %+v", backend, tt.field, tt.want, tt.date, commits)
+				continue
+			}
+			if commits[0].AuthorDate.Equal(commits[0].CommitterDate) {
+				t.Errorf("%s/%s: 作者日期和提交日期应不同: %+v", backend, tt.field, commits[0])
+			}
+		}
+	}
+	_ = SetBackend(BackendExec)
+}
+
+// TestGetCommitsBetweenAuthors 测试按多个作者筛选：名称中的正则特殊字符按字面匹配，
+// 邮箱同样可以匹配，并按.mailmap映射到同一身份，Co-authored-by中的共同作者也会匹配
+func TestGetCommitsBetweenAuthors(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil || os.Getenv("SKIP_GIT_TESTS") == "true" {
+		t.Skip("git命令不可用，跳过测试")
+	}
+
+	dir := t.TempDir()
+	run := func(name, email string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+name, "GIT_AUTHOR_EMAIL="+email, "GIT_AUTHOR_DATE=2025-05-20T10:00:00Z",
+			"GIT_COMMITTER_NAME="+name, "GIT_COMMITTER_EMAIL="+email, "GIT_COMMITTER_DATE=2025-05-20T10:00:00Z")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, output)
+		}
+	}
+	run("", "", "init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, ".mailmap"), []byte("Li Lei (Work) <lilei@work.example> <lilei@home.example>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("Li Lei (Work)", "lilei@work.example", "commit", "-q", "--allow-empty", "-m", "work")
+	run("lilei", "lilei@home.example", "commit", "-q", "--allow-empty", "-m", "home")
+	run("Han Meimei", "meimei@work.example", "commit", "-q", "--allow-empty", "-m", "colleague")
+	run("Li Lei", "lilei@laptop.example", "commit", "-q", "--allow-empty", "-m", "laptop")
+	run("Han Meimei", "meimei@work.example", "commit", "-q", "--allow-empty", "-m", "pair\n\nCo-authored-by: Li Lei (Work) <lilei@work.example>")
+
+	from := time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	authors := []string{regexp.QuoteMeta("Li Lei (Work)"), `@laptop\.example`}
+	for _, backend := range []Backend{BackendExec, BackendGoGit} {
+		if err := SetBackend(backend); err != nil {
+			t.Fatal(err)
+		}
+		commits, err := GetCommitsBetween(context.Background(), from, to, &Options{RepoPath: dir, Authors: authors})
+		if err != nil {
+			t.Fatalf("%s: 获取提交失败: %v", backend, err)
+		}
+
+		var messages []string
+		for _, commit := range commits {
+			messages = append(messages, commit.Message)
+			if commit.Message == "home" && (commit.Author != "Li Lei (Work)" || commit.AuthorEmail != "lilei@work.example") {
+				t.Errorf("%s: 作者应按.mailmap映射, 得到: %s <%s>", backend, commit.Author, commit.AuthorEmail)
+			}
+
+			// 结对编程的提交由同事提交，通过Co-authored-by匹配
+			wantRole := RoleAuthor
+			if commit.Message == "pair" {
+				wantRole = RoleCoAuthor
+			}
+			if commit.Role != wantRole {
+				t.Errorf("%s: 提交 %s 的角色应为 %s, 得到: %s", backend, commit.Message, wantRole, commit.Role)
+			}
+		}
+		sort.Strings(messages)
+		if strings.Join(messages, ",") != "home,laptop,pair,work" {
+			t.Errorf("%s: 应包含3个身份的提交和共同完成的提交, 得到: %v", backend, messages)
+		}
+	}
+	_ = SetBackend(BackendExec)
+}
+
+// TestParseCoAuthors 测试解析Co-authored-by尾注
+func TestParseCoAuthors(t *testing.T) {
+	message := "feat: 结对完成报告导出\n\n正文提到 co-authored-by: 不在行首 <x@example.com>\n\n" +
+		"Co-authored-by: Li Lei <lilei@example.com>\n" +
+		"co-authored-by:Han Meimei <meimei@example.com>  \n" +
+		"Co-Authored-By: Li Lei <LiLei@example.com>\n" +
+		"Signed-off-by: Jim Green <jim@example.com>\n"
+
+	coAuthors := parseCoAuthors(message)
+	want := []CoAuthor{{Name: "Li Lei", Email: "lilei@example.com"}, {Name: "Han Meimei", Email: "meimei@example.com"}}
+	if len(coAuthors) != len(want) {
+		t.Fatalf("应解析出 %d 个共同作者, 得到: %+v", len(want), coAuthors)
+	}
+	for i := range want {
+		if coAuthors[i] != want[i] {
+			t.Errorf("第 %d 个共同作者应为 %+v, 得到: %+v", i+1, want[i], coAuthors[i])
+		}
+	}
+
+	if commits, err := parseCommits(logRecord("abc123", "Jim Green", "2023-01-01T12:00:00+08:00", "", message, "")); err != nil || len(commits[0].CoAuthors) != 2 {
+		t.Errorf("parseCommits应解析共同作者: %+v (err: %v)", commits, err)
+	}
+}
+
+// TestParseDateField 测试解析日期字段名称
+func TestParseDateField(t *testing.T) {
+	for name, want := range map[string]DateField{"": DateFieldAuthor, "author": DateFieldAuthor, "Committer": DateFieldCommitter} {
+		if field, err := ParseDateField(name); err != nil || field != want {
+			t.Errorf("ParseDateField(%q) = %q, %v, 期望: %q", name, field, err, want)
+		}
+	}
+	if _, err := ParseDateField("commit"); err == nil {
+		t.Error("不支持的日期字段应返回错误")
 	}
 }
 
@@ -88,6 +410,64 @@ func TestGetGitUserName(t *testing.T) {
 	}
 }
 
+// FuzzParseCommits 对任意的作者和提交消息，解析结果的字段都不会错位
+func FuzzParseCommits(f *testing.F) {
+	f.Add("John Doe", "feat: 添加周报")
+	f.Add("John | Doe", "fix: a|b|c|d|e\n\n正文\n")
+	f.Add("Jane", "subject\n\nbody with \x1e record separator\n\n10\t2\tfake.go\n")
+	f.Add("", "")
+	f.Add("Jane", "\n\n   \n")
+
+	f.Fuzz(func(t *testing.T, author, message string) {
+		// 任意输出都不能导致panic
+		_, _ = parseCommits(author + message)
+
+		// Git不允许作者和提交消息中包含NUL，作者中也不会有换行
+		if strings.Contains(author, fieldSeparator) || strings.Contains(message, fieldSeparator) || strings.ContainsAny(author, "\n") {
+			t.Skip()
+		}
+
+		output := logRecord("abc123", author, "2023-01-01T12:00:00+08:00", "HEAD -> refs/heads/main", message, "1\t2\tmain.go\x00") +
+			logRecord("def456", "Jane Smith", "2023-01-02T13:00:00+08:00", "", "second\n", "")
+		commits, err := parseCommits(output)
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if len(commits) != 2 {
+			t.Fatalf("应解析出2个提交, 得到: %d", len(commits))
+		}
+
+		first := commits[0]
+		if first.Hash != "abc123" || first.Author != author || len(first.Branches) != 1 || first.Branches[0] != "main" {
+			t.Errorf("字段错位: %+v", first)
+		}
+		if len(first.ChangedFiles) != 1 || first.Insertions != 1 || first.Deletions != 2 {
+			t.Errorf("文件统计错位: %+v", first)
+		}
+
+		// 标题和正文都来自原始消息
+		subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+		if first.Message != strings.TrimSpace(subject) || first.Body != strings.TrimSpace(body) {
+			t.Errorf("消息解析错误: %q -> %q, %q", message, first.Message, first.Body)
+		}
+
+		if second := commits[1]; second.Hash != "def456" || second.Message != "second" || len(second.ChangedFiles) != 0 {
+			t.Errorf("第二个提交解析错误: %+v", second)
+		}
+	})
+}
+
+// logRecord 按logFormat的格式构造一条git log -z记录，作者邮箱固定，作者日期和提交日期相同，
+// numstat为--numstat -z的文件统计，每个字段以NUL结尾
+func logRecord(hash, author, date, refs, message, numstat string) string {
+	record := recordSeparator + strings.Join([]string{hash, author, "dev@example.com", date, date, refs, message}, fieldSeparator) + fieldSeparator
+	if numstat != "" {
+		record += "\n" + numstat
+	}
+	// -z时提交之间以NUL分隔
+	return record + fieldSeparator
+}
+
 // 辅助函数：检查切片是否包含指定元素
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,331 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GoGitRepository 使用go-git读取仓库，不依赖系统中安装的git命令，
+// 也可以包装内存中的仓库用于测试
+type GoGitRepository struct {
+	repo *gogit.Repository
+}
+
+// 确保GoGitRepository实现了Repository接口
+var _ Repository = (*GoGitRepository)(nil)
+
+// OpenGoGitRepository 使用go-git打开路径所在的仓库，路径可以是仓库中的子目录
+func OpenGoGitRepository(path string) (*GoGitRepository, error) {
+	repo, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("打开Git仓库 %s 失败: %w", path, err)
+	}
+	return NewGoGitRepository(repo), nil
+}
+
+// NewGoGitRepository 包装已经打开的go-git仓库，如gogit.Init(memory.NewStorage(), nil)创建的内存仓库
+func NewGoGitRepository(repo *gogit.Repository) *GoGitRepository {
+	return &GoGitRepository{repo: repo}
+}
+
+// CommitsBetween 遍历所有引用可达的提交，返回opts.DateField选择的日期在范围内的提交，按该日期倒序排列。
+// opts.Authors按正则表达式匹配按.mailmap映射后的 "作者名 <邮箱>" 以及Co-authored-by尾注中的共同作者
+func (r *GoGitRepository) CommitsBetween(ctx context.Context, fromDate, toDate time.Time, opts *Options) ([]CommitInfo, error) {
+	matchAuthor, err := authorMatcher(opts.authors())
+	if err != nil {
+		return nil, err
+	}
+
+	identities := r.mailmap()
+
+	branches, err := r.branchesByCommit()
+	if err != nil {
+		return nil, err
+	}
+
+	// 与git命令后端相同，go-git按提交日期筛选，按作者日期筛选时不限制结束时间
+	logOptions := &gogit.LogOptions{All: true, Since: &fromDate}
+	field := opts.dateField()
+	if field == DateFieldCommitter {
+		logOptions.Until = &toDate
+	}
+
+	iter, err := r.repo.Log(logOptions)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			// 还没有任何提交的仓库
+			return []CommitInfo{}, nil
+		}
+		return nil, fmt.Errorf("读取提交记录失败: %w", err)
+	}
+	defer iter.Close()
+
+	commits := make([]CommitInfo, 0)
+	err = iter.ForEach(func(c *object.Commit) error {
+		// 计算每个提交的差异可能较慢，每个提交之前检查是否已取消
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name, email := identities.lookup(c.Author.Name, c.Author.Email)
+		role, ok := matchRole(matchAuthor, name, email, parseCoAuthors(c.Message))
+		if !ok {
+			return nil
+		}
+
+		commit, err := newCommitInfo(c)
+		if err != nil {
+			return err
+		}
+		commit.Author, commit.AuthorEmail, commit.Role = name, email, role
+		commit.Branches = branches[c.Hash]
+		if commit.Branches == nil {
+			commit.Branches = []string{}
+		}
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取提交记录失败: %w", err)
+	}
+
+	// 遍历所有引用时各分支的提交是交错的，按选择的日期精确筛选并倒序排列
+	return selectByDate(commits, fromDate, toDate, field), nil
+}
+
+// CommitDetails 返回指定提交的详细信息，hash可以是完整或缩写的哈希以及分支名等修订号
+func (r *GoGitRepository) CommitDetails(_ context.Context, hash string) (*CommitInfo, error) {
+	revision, err := r.repo.ResolveRevision(plumbing.Revision(hash))
+	if err != nil {
+		return nil, fmt.Errorf("获取提交详情失败: %w", err)
+	}
+
+	c, err := r.repo.CommitObject(*revision)
+	if err != nil {
+		return nil, fmt.Errorf("获取提交详情失败: %w", err)
+	}
+
+	commit, err := newCommitInfo(c)
+	if err != nil {
+		return nil, fmt.Errorf("解析提交详情失败: %w", err)
+	}
+	commit.Author, commit.AuthorEmail = r.mailmap().lookup(c.Author.Name, c.Author.Email)
+
+	branches, err := r.branchesByCommit()
+	if err != nil {
+		return nil, err
+	}
+	commit.Branches = branches[c.Hash]
+	return &commit, nil
+}
+
+// UserName 返回仓库配置的用户名，仓库中没有配置时使用全局配置
+func (r *GoGitRepository) UserName() (string, error) {
+	cfg, err := r.repo.ConfigScoped(config.GlobalScope)
+	if err != nil {
+		return "", fmt.Errorf("获取Git用户名失败: %w", err)
+	}
+	if cfg.User.Name == "" {
+		return "", fmt.Errorf("获取Git用户名失败: 没有配置user.name")
+	}
+	return cfg.User.Name, nil
+}
+
+// UserEmail 返回仓库配置的邮箱，仓库中没有配置时使用全局配置
+func (r *GoGitRepository) UserEmail() (string, error) {
+	cfg, err := r.repo.ConfigScoped(config.GlobalScope)
+	if err != nil {
+		return "", fmt.Errorf("获取Git邮箱失败: %w", err)
+	}
+	if cfg.User.Email == "" {
+		return "", fmt.Errorf("获取Git邮箱失败: 没有配置user.email")
+	}
+	return cfg.User.Email, nil
+}
+
+// mailmap 读取仓库中的.mailmap，与git命令相同，普通仓库读取工作区中的文件，
+// 裸仓库读取HEAD提交中的文件。没有.mailmap或读取失败时不做映射
+func (r *GoGitRepository) mailmap() *mailmap {
+	if worktree, err := r.repo.Worktree(); err == nil {
+		content, err := util.ReadFile(worktree.Filesystem, ".mailmap")
+		if err != nil {
+			return nil
+		}
+		return parseMailmap(string(content))
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil
+	}
+	file, err := commit.File(".mailmap")
+	if err != nil {
+		return nil
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil
+	}
+	return parseMailmap(content)
+}
+
+// branchesByCommit 返回每个提交上的分支名称，与git log的%D相同，
+// 只包含直接指向该提交的本地和远程分支，名称经过与git命令后端相同的branchName处理
+func (r *GoGitRepository) branchesByCommit() (map[plumbing.Hash][]string, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("读取分支失败: %w", err)
+	}
+
+	seen := make(map[plumbing.Hash]map[string]bool)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		branch, ok := branchName(ref.Name().String())
+		if !ok {
+			return nil
+		}
+
+		if seen[ref.Hash()] == nil {
+			seen[ref.Hash()] = make(map[string]bool)
+		}
+		seen[ref.Hash()][branch] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取分支失败: %w", err)
+	}
+
+	branches := make(map[plumbing.Hash][]string, len(seen))
+	for hash, names := range seen {
+		branches[hash] = sortedNames(names)
+	}
+	return branches, nil
+}
+
+// sortedNames 返回集合中按字母排序的名称
+func sortedNames(names map[string]bool) []string {
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// newCommitInfo 将go-git的提交转换为CommitInfo，包括与git log --numstat -M相同的文件统计
+func newCommitInfo(c *object.Commit) (CommitInfo, error) {
+	subject, body := splitMessage(c.Message)
+	commit := CommitInfo{
+		Hash:          c.Hash.String(),
+		Author:        c.Author.Name,
+		AuthorEmail:   c.Author.Email,
+		Date:          c.Author.When,
+		AuthorDate:    c.Author.When,
+		CommitterDate: c.Committer.When,
+		Message:       subject,
+		Body:          body,
+		CoAuthors:     parseCoAuthors(c.Message),
+	}
+
+	// 与git log相同，合并提交不输出文件统计
+	if c.NumParents() > 1 {
+		return commit, nil
+	}
+
+	stats, err := commitFileStats(c)
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("统计提交 %s 的变更失败: %w", c.Hash, err)
+	}
+	for _, stat := range stats {
+		commit.addFileStat(stat)
+	}
+	return commit, nil
+}
+
+// commitFileStats 对比提交和第一个父提交，统计每个文件新增和删除的行数，识别重命名和二进制文件
+func commitFileStats(c *object.Commit) ([]FileStat, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	parentTree := &object.Tree{}
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+
+	// DiffTree的默认选项会识别重命名
+	patch, err := parentTree.Patch(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]FileStat, 0, len(patch.FilePatches()))
+	for _, filePatch := range patch.FilePatches() {
+		from, to := filePatch.Files()
+
+		var stat FileStat
+		switch {
+		case to == nil:
+			stat.Path = from.Path()
+		case from == nil:
+			stat.Path = to.Path()
+		default:
+			stat.Path = to.Path()
+			if from.Path() != to.Path() {
+				stat.OldPath = from.Path()
+			}
+		}
+
+		if filePatch.IsBinary() {
+			stat.Binary = true
+			stats = append(stats, stat)
+			continue
+		}
+
+		for _, chunk := range filePatch.Chunks() {
+			lines := countLines(chunk.Content())
+			switch chunk.Type() {
+			case diff.Add:
+				stat.Insertions += lines
+			case diff.Delete:
+				stat.Deletions += lines
+			}
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// countLines 统计文本的行数，最后一行没有换行符时也计为一行
+func countLines(content string) int {
+	lines := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,299 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// memoryRepo 在内存中创建仓库的测试辅助对象
+type memoryRepo struct {
+	t        *testing.T
+	repo     *gogit.Repository
+	worktree *gogit.Worktree
+}
+
+// newMemoryRepo 创建内存中的空仓库
+func newMemoryRepo(t *testing.T) *memoryRepo {
+	t.Helper()
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("创建内存仓库失败: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("获取工作区失败: %v", err)
+	}
+	return &memoryRepo{t: t, repo: repo, worktree: worktree}
+}
+
+// commit 写入文件并提交，files中值为空的文件会被删除
+func (m *memoryRepo) commit(author, message string, when time.Time, files map[string]string) plumbing.Hash {
+	m.t.Helper()
+	for name, content := range files {
+		if content == "" {
+			if _, err := m.worktree.Remove(name); err != nil {
+				m.t.Fatalf("删除文件失败: %v", err)
+			}
+			continue
+		}
+		if err := util.WriteFile(m.worktree.Filesystem, name, []byte(content), 0o644); err != nil {
+			m.t.Fatalf("写入文件失败: %v", err)
+		}
+		if _, err := m.worktree.Add(name); err != nil {
+			m.t.Fatalf("添加文件失败: %v", err)
+		}
+	}
+
+	signature := &object.Signature{Name: author, Email: author + "@example.com", When: when}
+	hash, err := m.worktree.Commit(message, &gogit.CommitOptions{Author: signature, Committer: signature, AllowEmptyCommits: true})
+	if err != nil {
+		m.t.Fatalf("提交失败: %v", err)
+	}
+	return hash
+}
+
+// TestGoGitRepository 测试go-git后端读取内存仓库的提交、文件统计和分支
+func TestGoGitRepository(t *testing.T) {
+	m := newMemoryRepo(t)
+	day := time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	m.commit("alice", "chore: 初始化", day.AddDate(0, 0, -10), map[string]string{"old.go": "package a\n\nfunc A() {}\n"})
+	m.commit("alice", "feat: 添加周报\n\n支持按仓库分组", day, map[string]string{
+		"report.go": "package report\n\nfunc Report() {}\n",
+		"logo.png":  "\x89PNG\x00\x01\x02",
+	})
+	m.commit("bob", "refactor: 重命名", day.Add(time.Hour), map[string]string{
+		"old.go": "",
+		"new.go": "package a\n\nfunc A() {}\n",
+	})
+	last := m.commit("alice", "fix: 修复 a|b", day.Add(2*time.Hour), map[string]string{"report.go": "package report\n\nfunc Report() { fix() }\n"})
+	if err := m.worktree.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}); err != nil {
+		t.Fatalf("创建分支失败: %v", err)
+	}
+
+	repo := NewGoGitRepository(m.repo)
+	commits, err := repo.CommitsBetween(context.Background(), day.Add(-time.Hour), day.AddDate(0, 0, 1), nil)
+	if err != nil {
+		t.Fatalf("读取提交失败: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("时间范围内应有3个提交, 得到: %d", len(commits))
+	}
+
+	// 按时间倒序排列，最新的提交带有分支信息
+	newest := commits[0]
+	if newest.Hash != last.String() || newest.Message != "fix: 修复 a|b" || newest.Insertions != 1 || newest.Deletions != 1 {
+		t.Errorf("最新的提交不正确: %+v", newest)
+	}
+	if len(newest.Branches) != 2 || newest.Branches[0] != "feature" || newest.Branches[1] != "master" {
+		t.Errorf("分支应为 [feature master], 得到: %v", newest.Branches)
+	}
+
+	if renames := commits[1].Renames(); len(renames) != 1 || renames[0].OldPath != "old.go" || renames[0].Path != "new.go" {
+		t.Errorf("应识别重命名, 得到: %+v", commits[1].FileStats)
+	}
+
+	feature := commits[2]
+	if feature.Body != "支持按仓库分组" || feature.Insertions != 3 || len(feature.ChangedFiles) != 2 {
+		t.Errorf("提交信息或文件统计不正确: %+v", feature)
+	}
+	for _, stat := range feature.FileStats {
+		if stat.Path == "logo.png" && !stat.Binary {
+			t.Error("logo.png应标记为二进制文件")
+		}
+	}
+
+	// 与git log --author相同，按正则匹配作者名和邮箱
+	authored, err := repo.CommitsBetween(context.Background(), day.Add(-time.Hour), day.AddDate(0, 0, 1), &Options{Authors: []string{"bob@"}})
+	if err != nil || len(authored) != 1 || authored[0].Author != "bob" {
+		t.Errorf("作者筛选不正确: %+v (err: %v)", authored, err)
+	}
+
+	details, err := repo.CommitDetails(context.Background(), last.String()[:8])
+	if err != nil || details.Hash != last.String() {
+		t.Errorf("获取提交详情失败: %+v (err: %v)", details, err)
+	}
+}
+
+// TestGoGitRepositoryMailmap 测试go-git后端按.mailmap映射作者，并默认匹配当前用户的名称和邮箱
+func TestGoGitRepositoryMailmap(t *testing.T) {
+	m := newMemoryRepo(t)
+	day := time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	m.commit("alice", "feat: 公司电脑", day, map[string]string{".mailmap": "Alice Wang <alice@example.com>\nAlice Wang <alice@example.com> <al@example.com>\n"})
+	m.commit("al", "feat: 家里电脑", day.Add(time.Hour), map[string]string{"a.go": "package a\n"})
+	m.commit("bob", "feat: 其他人", day.Add(2*time.Hour), map[string]string{"b.go": "package b\n"})
+
+	cfg, err := m.repo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Name, cfg.User.Email = "Alice Wang", "alice@example.com"
+	if err := m.repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewGoGitRepository(m.repo)
+	identities := userIdentities(repo)
+	if len(identities) != 2 || identities[1] != `alice@example\.com` {
+		t.Fatalf("应包含转义后的用户名和邮箱, 得到: %v", identities)
+	}
+
+	commits, err := repo.CommitsBetween(context.Background(), day.Add(-time.Hour), day.AddDate(0, 0, 1), &Options{Authors: identities})
+	if err != nil {
+		t.Fatalf("读取提交失败: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("应包含两个身份的2个提交, 得到: %+v", commits)
+	}
+	for _, commit := range commits {
+		if commit.Author != "Alice Wang" || commit.AuthorEmail != "alice@example.com" {
+			t.Errorf("作者应按.mailmap映射, 得到: %s <%s>", commit.Author, commit.AuthorEmail)
+		}
+	}
+}
+
+// TestBackendParity 测试两种后端对同一仓库返回相同的提交和文件统计，包括非ASCII和特殊字符路径的重命名
+func TestBackendParity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git命令不可用，跳过测试")
+	}
+
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("创建仓库失败: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("获取工作区失败: %v", err)
+	}
+	m := &memoryRepo{t: t, repo: repo, worktree: worktree}
+
+	content := "第一行\n第二行\n第三行\n第四行\n"
+	day := time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC)
+	m.commit("alice", "chore: 初始化", day, map[string]string{
+		"文件.txt":  content,
+		"main.go": "package main\n",
+	})
+	feature := m.commit("alice", "feat: 特殊路径", day.Add(time.Hour), map[string]string{
+		"a => b.txt": "箭头\n",
+		"d{1}/f.txt": "大括号\n",
+		"main.go":    "package main\n\nfunc main() {}\n",
+	})
+	m.commit("bob", "refactor: 重命名", day.Add(2*time.Hour), map[string]string{
+		"文件.txt":  "",
+		"新文件.txt": content,
+	})
+
+	// 名称包含斜杠的本地分支和远程分支
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName("feature/login"),
+		plumbing.NewRemoteReferenceName("origin", "release/v1"),
+	} {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(name, feature)); err != nil {
+			t.Fatalf("创建分支失败: %v", err)
+		}
+	}
+
+	from, to := day.Add(-time.Hour), day.AddDate(0, 0, 1)
+	execCommits, err := NewExecRepository(dir).CommitsBetween(context.Background(), from, to, &Options{})
+	if err != nil {
+		t.Fatalf("git命令后端读取提交失败: %v", err)
+	}
+	goGitRepo, err := OpenGoGitRepository(dir)
+	if err != nil {
+		t.Fatalf("go-git打开仓库失败: %v", err)
+	}
+	goGitCommits, err := goGitRepo.CommitsBetween(context.Background(), from, to, &Options{})
+	if err != nil {
+		t.Fatalf("go-git后端读取提交失败: %v", err)
+	}
+
+	if len(execCommits) != 3 || len(goGitCommits) != len(execCommits) {
+		t.Fatalf("两种后端都应返回3个提交, 得到: %d, %d", len(execCommits), len(goGitCommits))
+	}
+	for i, expected := range execCommits {
+		actual := goGitCommits[i]
+		if actual.Hash != expected.Hash || actual.Insertions != expected.Insertions || actual.Deletions != expected.Deletions {
+			t.Errorf("提交 %d 不一致: git %s +%d -%d, go-git %s +%d -%d", i,
+				expected.Hash, expected.Insertions, expected.Deletions, actual.Hash, actual.Insertions, actual.Deletions)
+		}
+		if strings.Join(actual.Branches, ",") != strings.Join(expected.Branches, ",") {
+			t.Errorf("提交 %s 的分支不一致: git %v, go-git %v", expected.Message, expected.Branches, actual.Branches)
+		}
+		if got, want := sortedFileStats(actual.FileStats), sortedFileStats(expected.FileStats); !equalFileStats(got, want) {
+			t.Errorf("提交 %s 的文件统计不一致:\ngit:    %+v\ngo-git: %+v", expected.Message, want, got)
+		}
+	}
+
+	// 按作者筛选时git命令后端只为匹配的提交获取文件统计，结果应与不筛选时相同
+	authorCommits, err := NewExecRepository(dir).CommitsBetween(context.Background(), from, to, &Options{Authors: []string{"^alice "}})
+	if err != nil {
+		t.Fatalf("git命令后端按作者读取提交失败: %v", err)
+	}
+	if len(authorCommits) != 2 {
+		t.Fatalf("应只返回alice的2个提交, 得到: %d", len(authorCommits))
+	}
+	for i, commit := range authorCommits {
+		expected := execCommits[i+1]
+		if commit.Hash != expected.Hash || commit.Role != RoleAuthor || commit.Insertions != expected.Insertions ||
+			!equalFileStats(sortedFileStats(commit.FileStats), sortedFileStats(expected.FileStats)) {
+			t.Errorf("按作者筛选后的文件统计不一致:\n得到: %+v\n期望: %+v", commit, expected)
+		}
+	}
+
+	if branches := execCommits[1].Branches; strings.Join(branches, ",") != "feature/login,release/v1" {
+		t.Errorf("包含斜杠的分支名称应保持完整, 得到: %v", branches)
+	}
+	if renames := execCommits[0].Renames(); len(renames) != 1 || renames[0].OldPath != "文件.txt" || renames[0].Path != "新文件.txt" {
+		t.Errorf("非ASCII路径的重命名应被识别, 得到: %+v", renames)
+	}
+}
+
+// sortedFileStats 返回按路径排序的文件统计副本
+func sortedFileStats(stats []FileStat) []FileStat {
+	sorted := append([]FileStat(nil), stats...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	return sorted
+}
+
+// equalFileStats 比较两组文件统计是否相同
+func equalFileStats(a, b []FileStat) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSetBackend 测试选择Git后端
+func TestSetBackend(t *testing.T) {
+	defer func() { _ = SetBackend(BackendExec) }()
+
+	if err := SetBackend(Backend("svn")); err == nil {
+		t.Error("不支持的后端应返回错误")
+	}
+
+	if err := SetBackend(BackendGoGit); err != nil {
+		t.Fatalf("设置go-git后端失败: %v", err)
+	}
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("go-git打开不是仓库的目录应返回错误")
+	}
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mailmapIdentityPattern 匹配.mailmap中的一个身份，如 "Name <email>" 或 "<email>"
+var mailmapIdentityPattern = regexp.MustCompile(`([^<>]*)<([^<>]*)>`)
+
+// mailmapEntry .mailmap中的一条映射
+type mailmapEntry struct {
+	name       string // 规范的名称，为空时保留提交中的名称
+	email      string // 规范的邮箱，为空时保留提交中的邮箱
+	commitName string // 提交中的名称，为空时匹配该邮箱的所有名称
+}
+
+// mailmap .mailmap文件中的身份映射，go-git后端用它实现与git命令相同的作者映射
+type mailmap struct {
+	entries map[string][]mailmapEntry // 按小写的提交邮箱索引
+}
+
+// parseMailmap 解析.mailmap文件，支持git文档中的四种格式：
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func parseMailmap(content string) *mailmap {
+	m := &mailmap{entries: make(map[string][]mailmapEntry)}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		identities := mailmapIdentityPattern.FindAllStringSubmatch(line, 2)
+		var entry mailmapEntry
+		var commitEmail string
+		switch len(identities) {
+		case 1:
+			entry.name = strings.TrimSpace(identities[0][1])
+			commitEmail = identities[0][2]
+		case 2:
+			entry.name = strings.TrimSpace(identities[0][1])
+			entry.email = strings.TrimSpace(identities[0][2])
+			entry.commitName = strings.TrimSpace(identities[1][1])
+			commitEmail = identities[1][2]
+		default:
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(commitEmail))
+		m.entries[key] = append(m.entries[key], entry)
+	}
+	return m
+}
+
+// lookup 返回提交中的名称和邮箱映射后的规范身份，没有匹配的映射时原样返回。
+// 与git相同，邮箱和名称都不区分大小写，同时匹配名称和邮箱的映射优先，同一身份有多条映射时后面的生效
+func (m *mailmap) lookup(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+
+	var matched *mailmapEntry
+	var matchedName bool
+	entries := m.entries[strings.ToLower(email)]
+	for i := range entries {
+		entry := &entries[i]
+		switch {
+		case entry.commitName != "" && strings.EqualFold(entry.commitName, name):
+			matched, matchedName = entry, true
+		case entry.commitName == "" && !matchedName:
+			matched = entry
+		}
+	}
+	if matched == nil {
+		return name, email
+	}
+
+	if matched.name != "" {
+		name = matched.name
+	}
+	if matched.email != "" {
+		email = matched.email
+	}
+	return name, email
+}
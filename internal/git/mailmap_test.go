@@ -0,0 +1,40 @@
+package git
+
+import "testing"
+
+// TestMailmapLookup 测试.mailmap四种格式的映射，邮箱和名称不区分大小写
+func TestMailmapLookup(t *testing.T) {
+	m := parseMailmap(`# 团队成员的身份映射
+Alice Wang <alice@home.example>
+<bob@work.example> <bob@home.example>
+Carol Li <carol@work.example> <carol@old.example>
+Dave Zhao <dave@work.example> dave <shared@example.com>
+Eve <eve@work.example> eve <shared@example.com>
+not an identity
+`)
+
+	tests := []struct {
+		name, email         string
+		wantName, wantEmail string
+	}{
+		{"alice", "Alice@Home.example", "Alice Wang", "Alice@Home.example"},
+		{"bob", "bob@home.example", "bob", "bob@work.example"},
+		{"carol", "carol@old.example", "Carol Li", "carol@work.example"},
+		{"Dave", "shared@example.com", "Dave Zhao", "dave@work.example"},
+		{"eve", "shared@example.com", "Eve", "eve@work.example"},
+		{"frank", "shared@example.com", "frank", "shared@example.com"},
+		{"gina", "gina@example.com", "gina", "gina@example.com"},
+	}
+	for _, tt := range tests {
+		name, email := m.lookup(tt.name, tt.email)
+		if name != tt.wantName || email != tt.wantEmail {
+			t.Errorf("lookup(%q, %q) = %q, %q, 期望: %q, %q", tt.name, tt.email, name, email, tt.wantName, tt.wantEmail)
+		}
+	}
+
+	// 没有.mailmap时原样返回
+	var empty *mailmap
+	if name, email := empty.lookup("gina", "gina@example.com"); name != "gina" || email != "gina@example.com" {
+		t.Errorf("没有映射时应原样返回, 得到: %q, %q", name, email)
+	}
+}
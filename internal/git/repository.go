@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Repository 读取Git仓库提交记录的后端
+type Repository interface {
+	// CommitsBetween 返回所有分支在指定时间范围内的提交，按opts.DateField选择的日期筛选并倒序排列，
+	// opts.Authors不为空时只返回作者匹配其中任意一个的提交，opts为nil时使用默认选项。
+	// 作者名称和邮箱按仓库中的.mailmap映射后再匹配。ctx取消时停止读取并返回ctx的错误
+	CommitsBetween(ctx context.Context, fromDate, toDate time.Time, opts *Options) ([]CommitInfo, error)
+	// CommitDetails 返回指定提交的详细信息
+	CommitDetails(ctx context.Context, hash string) (*CommitInfo, error)
+	// UserName 返回仓库配置的Git用户名
+	UserName() (string, error)
+	// UserEmail 返回仓库配置的Git邮箱
+	UserEmail() (string, error)
+}
+
+// Backend Git后端的名称
+type Backend string
+
+const (
+	// BackendExec 执行系统中安装的git命令
+	BackendExec Backend = "exec"
+	// BackendGoGit 使用纯Go实现的go-git，不需要安装git
+	BackendGoGit Backend = "go-git"
+)
+
+// currentBackend 打开仓库时使用的后端
+var currentBackend = BackendExec
+
+// Backends 返回所有支持的后端名称
+func Backends() []string {
+	return []string{string(BackendExec), string(BackendGoGit)}
+}
+
+// SetBackend 设置打开仓库时使用的后端
+func SetBackend(backend Backend) error {
+	switch backend {
+	case BackendExec, BackendGoGit:
+		currentBackend = backend
+		return nil
+	default:
+		return fmt.Errorf("不支持的Git后端: %s", backend)
+	}
+}
+
+// Open 使用当前设置的后端打开仓库
+func Open(path string) (Repository, error) {
+	if currentBackend == BackendGoGit {
+		return OpenGoGitRepository(path)
+	}
+	return NewExecRepository(path), nil
+}
@@ -0,0 +1,196 @@
+package i18n
+
+// english 英文消息表
+var english = map[string]string{
+	// 报告类型
+	"工作日报":      "Daily Report",
+	"工作周报":      "Weekly Report",
+	"工作月报":      "Monthly Report",
+	"工作年报":      "Annual Report",
+	"工作报告":      "Work Report",
+	"日报":        "daily report",
+	"周报":        "weekly report",
+	"月报":        "monthly report",
+	"年报":        "annual report",
+	"报告":        "report",
+	"自定义时间范围报告": "custom range report",
+
+	// 报告内容
+	"%s (%s 至 %s)\n":        "%s (%s to %s)\n",
+	"# %s (%s 至 %s)\n\n":    "# %s (%s to %s)\n\n",
+	"## 仓库统计":               "## Repositories",
+	"- %s: %d 条提交\n":        "- %s: %d commits\n",
+	"- **%s**: %d 条提交\n":    "- **%s**: %d commits\n",
+	"## AI 总结":              "## AI Summary",
+	"## 提交记录":               "## Commits",
+	"共有 %d 条提交记录\n\n":       "%d commits in total\n\n",
+	"提交 %d:\n":              "Commit %d:\n",
+	"### 提交 %d\n\n":         "### Commit %d\n\n",
+	"- 哈希值: %s\n":           "- Hash: %s\n",
+	"- 作者: %s\n":            "- Author: %s\n",
+	"- 共同作者: %s\n":          "- Co-authors: %s\n",
+	"- 角色: %s\n":            "- Role: %s\n",
+	"- 日期: %s\n":            "- Date: %s\n",
+	"- 仓库: %s\n":            "- Repository: %s\n",
+	"- 分支: %s\n":            "- Branches: %s\n",
+	"- 消息: %s\n":            "- Message: %s\n",
+	"- 消息: %s\n\n":          "- Message: %s\n\n",
+	"- 变更行数: +%d -%d\n":     "- Lines changed: +%d -%d\n",
+	"- **变更行数**: +%d -%d\n": "- **Lines changed**: +%d -%d\n",
+	"- 说明:\n":               "- Details:\n",
+	"- **说明**:":             "- **Details**:",
+	"- 变更文件:\n":             "- Changed files:\n",
+	"  * ... 以及其他 %d 个文件\n": "  * ... and %d more files\n",
+	"- **哈希值**: `%s`\n":     "- **Hash**: `%s`\n",
+	"- **作者**: %s\n":        "- **Author**: %s\n",
+	"- **共同作者**: %s\n":      "- **Co-authors**: %s\n",
+	"- **角色**: %s\n":        "- **Role**: %s\n",
+	"- **日期**: %s\n":        "- **Date**: %s\n",
+	"- **仓库**: `%s`\n":      "- **Repository**: `%s`\n",
+	"- **分支**: %s\n":        "- **Branches**: %s\n",
+	"- **消息**: %s\n":        "- **Message**: %s\n",
+	"- **变更文件**:":           "- **Changed files**:",
+	"已生成Markdown报告: %s\n":   "Markdown report generated: %s\n",
+
+	// HTML报告
+	"%s (%s 至 %s)": "%s (%s to %s)",
+	"%s 至 %s":      "%s to %s",
+	"共有 %d 条提交记录":  "%d commits in total",
+	"%d 条提交":       "%d commits",
+	"仓库统计":         "Repositories",
+	"AI 总结":        "AI Summary",
+	"提交记录":         "Commits",
+	"仓库":           "Repository",
+	"提交数":          "Commits",
+	"作者数":          "Authors",
+	"变更文件数":        "Changed files",
+	"日期":           "Date",
+	"哈希值":          "Hash",
+	"作者":           "Author",
+	"共同作者":         "Co-author",
+	"角色":           "Role",
+	"分支":           "Branches",
+	"消息":           "Message",
+	"变更文件":         "Changed files",
+	"变更行数":         "Lines changed",
+
+	// CSV报告
+	"标题":   "Subject",
+	"新增行数": "Lines added",
+	"删除行数": "Lines removed",
+	"首次提交": "First commit",
+	"最后提交": "Last commit",
+
+	// 结构化摘要
+	"总体概述":       "Overview",
+	"总体概述: %s\n": "Overview: %s\n",
+	"主要成果":       "Achievements",
+	"进行中的工作":     "In Progress",
+	"风险与问题":      "Risks and Issues",
+	"下一步计划":      "Next Steps",
+	"- 无":        "- None",
+
+	// 离线规则摘要
+	"没有找到提交记录。":                            "No commits found.",
+	"共 %d 条提交，涉及 %d 个仓库，时间范围 %s 至 %s。\n\n": "%d commits across %d repositories, from %s to %s.\n\n",
+	"共 %d 条提交，涉及 %d 个仓库，时间范围 %s 至 %s。":     "%d commits across %d repositories, from %s to %s.",
+	"%s %d 条":             "%s: %d",
+	"按类型统计：%s\n":          "By type: %s\n",
+	"，":                   ", ",
+	"包含 %d 条破坏性变更\n":      "Includes %d breaking changes\n",
+	"当前仓库":                "current repository",
+	"未标记分支":               "untagged branch",
+	"\n### %s (%d 条提交)\n": "\n### %s (%d commits)\n",
+	"\n#### 分支: %s\n":     "\n#### Branch: %s\n",
+	" [破坏性变更]":            " [breaking change]",
+	"破坏性变更":               "Breaking change",
+	"新功能":                 "Features",
+	"问题修复":                "Bug fixes",
+	"性能优化":                "Performance",
+	"重构":                  "Refactoring",
+	"文档":                  "Documentation",
+	"测试":                  "Tests",
+	"构建":                  "Build",
+	"持续集成":                "CI",
+	"代码格式":                "Code style",
+	"杂项":                  "Chores",
+	"回滚":                  "Reverts",
+	"其他":                  "Other",
+
+	// 分段总结的分段名称
+	"仓库 %s":         "Repository %s",
+	"%d年第%02d周":     "%d week %02d",
+	"%s 第 %d/%d 部分": "%s part %d/%d",
+
+	// 命令行消息
+	"错误: %v\n":            "Error: %v\n",
+	"错误: 创建AI客户端失败: %v\n": "Error: failed to create AI client: %v\n",
+	"警告: 创建AI客户端失败: %v，使用离线规则摘要\n":                         "Warning: failed to create AI client: %v, using the offline heuristic summary\n",
+	"警告: 当前AI提供方不支持结构化输出，使用普通摘要":                           "Warning: the current AI provider does not support structured output, using a plain summary",
+	"警告: %v，不使用AI摘要缓存\n":                                   "Warning: %v, AI summary cache disabled\n",
+	"错误: 报告模板无效: %v\n":                                     "Error: invalid report template: %v\n",
+	"错误: 提示词无效: %v\n":                                      "Error: invalid prompt: %v\n",
+	"错误: --csv-daily 需要 --format csv，且不能与 --template 同时使用": "Error: --csv-daily requires --format csv and cannot be used with --template",
+	"正在生成报告...\n":                                          "Generating report...\n",
+	"使用AI生成摘要...":                                          "Generating AI summary...",
+	"已取消生成报告":                                              "Report generation cancelled",
+	"错误: 生成报告摘要失败: %v\n":                                   "Error: failed to generate report summary: %v\n",
+	"创建输出文件失败: %v\n":                                       "Failed to create output file: %v\n",
+	"错误: 输出报告失败: %v\n":                                     "Error: failed to write report: %v\n",
+	"%s生成完成！\n":                                            "Finished generating %s!\n",
+	"提示词来源: %s\n":                                          "Prompt source: %s\n",
+	"错误: 构建提示词失败: %v\n":                                    "Error: failed to build prompt: %v\n",
+	"写入提示词文件失败: %v\n":                                      "Failed to write prompt file: %v\n",
+	"已将提示词写入: %s\n":                                        "Prompt written to: %s\n",
+	"---------- 提示词 ----------":                            "---------- Prompt ----------",
+	"提示词大小: %d 字节, %d 个字符, 约 %d tokens\n":                  "Prompt size: %d bytes, %d characters, about %d tokens\n",
+	"提示: 超过单个提示词的上限 %d tokens，生成报告时会按--chunk-strategy分为 %d 段分别总结，以上为不分段时的提示词\n": "Note: exceeds the per-prompt limit of %d tokens, the report will be summarized in %d chunks according to --chunk-strategy; the prompt above is the unchunked one\n",
+	"使用自定义提示词文件: %s\n":                 "Using custom prompt file: %s\n",
+	"使用基础提示词生成报告":                      "Using the basic prompt",
+	"使用详细提示词生成报告":                      "Using the detailed prompt",
+	"使用针对性提示词生成报告":                     "Using the targeted prompt",
+	"使用默认基础提示词生成报告":                    "Using the default basic prompt",
+	"错误: 日期格式不正确，请使用YYYY-MM-DD格式":      "Error: invalid date format, please use YYYY-MM-DD",
+	"使用自定义时间范围: %s 到 %s\n":             "Using custom time range: %s to %s\n",
+	"使用指定日期: %s\n":                     "Using date: %s\n",
+	"使用预定义时间范围 %s: %s 到 %s\n":          "Using predefined time range %s: %s to %s\n",
+	"错误: 发现Git仓库失败: %v\n":              "Error: failed to discover Git repositories: %v\n",
+	"在目录 %s 下没有发现任何Git仓库\n":            "No Git repositories found under %s\n",
+	"\n处理 %d 个仓库:\n":                   "\nProcessing %d repositories:\n",
+	"正在分析仓库: %s\n":                     "Analyzing repository: %s\n",
+	"  警告: 仓库 %s 获取Git提交记录失败: %v\n":    "  Warning: failed to read Git commits from %s: %v\n",
+	"  找到 %d 条提交记录\n":                  "  Found %d commits\n",
+	"\n=== 提交记录统计 ===\n":               "\n=== Commit Statistics ===\n",
+	"  %s: %d 条提交\n":                   "  %s: %d commits\n",
+	"总计: %d 条提交\n\n":                   "Total: %d commits\n\n",
+	"指定时间范围 %s 到 %s 在所有仓库中都没有找到提交记录\n": "No commits found in any repository between %s and %s\n",
+	"筛选作者: %s\n":                       "Filtering by author: %s\n",
+	"获取所有作者的提交":                        "Including commits from all authors",
+	"提示: 当前AI提供方不支持流式输出":               "Note: the current AI provider does not support streaming output",
+	"\n---------- AI 摘要 ----------":    "\n---------- AI Summary ----------",
+	"提示: 结构化输出不支持流式输出":                 "Note: structured output does not support streaming",
+	"使用JSON模式生成结构化摘要":                  "Generating a structured summary in JSON mode",
+	"git-work-log 版本: %s\n":            "git-work-log version: %s\n",
+	"提交哈希: %s\n":                       "Commit: %s\n",
+	"构建日期: %s\n":                       "Built: %s\n",
+	"# 来源: %s\n":                       "# Source: %s\n",
+	"预设":                               "preset",
+	"自定义":                              "custom",
+	"已删除 %d 条缓存的AI摘要 (%s)\n":           "Removed %d cached AI summaries (%s)\n",
+	"已创建提示词: %s\n":                     "Created prompt: %s\n",
+	"编辑后使用 --prompt %s 生成报告\n":         "Edit it, then generate a report with --prompt %s\n",
+	"  提示: 没有引用{{.CommitMessages}}或{{.Commits}}，提交记录将追加在末尾\n": "  Note: does not reference {{.CommitMessages}} or {{.Commits}}, commits will be appended at the end\n",
+	"正在扫描目录: %s\n":         "Scanning directory: %s\n",
+	"  发现Git仓库: %s\n":      "  Found Git repository: %s\n",
+	"扫描完成，共发现 %d 个Git仓库\n": "Scan finished, found %d Git repositories\n",
+
+	// AI摘要过程中的消息
+	"使用缓存的AI摘要 (使用--no-cache重新生成)":                            "Using cached AI summary (use --no-cache to regenerate)",
+	"警告: 写入AI摘要缓存失败: %v\n":                                    "Warning: failed to write AI summary cache: %v\n",
+	"提示词约 %d tokens，超过上限 %d，分为 %d 段分别总结\n":                    "Prompt is about %d tokens, exceeding the limit of %d, summarizing in %d chunks\n",
+	"  正在总结第 %d/%d 段: %s (%d 条提交)\n":                          "  Summarizing chunk %d/%d: %s (%d commits)\n",
+	"  分段摘要仍然过长，合并 %d 段摘要\n":                                  "  Chunk summaries are still too long, merging %d summaries\n",
+	"警告: 提示词模板没有引用{{.CommitMessages}}，无法填入分段摘要，使用默认提示词生成最终报告": "Warning: the prompt template does not reference {{.CommitMessages}}, so the chunk summaries cannot be filled in; using the default prompt for the final report",
+	"警告: 加载提示词模板失败: %v, 使用默认提示词\n":                            "Warning: failed to load prompt template: %v, using the default prompt\n",
+	"  AI请求失败: %v，%s后进行第 %d/%d 次重试\n":                         "  AI request failed: %v, retry %[3]d/%[4]d in %[2]s\n",
+}
@@ -0,0 +1,85 @@
+// Package i18n 提供报告和命令行消息的多语言支持。
+// 代码中直接使用中文文本，其他语言的翻译以中文文本为键保存在消息表中，
+// 没有翻译的文本原样输出
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Language 表示报告和命令行消息的语言
+type Language string
+
+const (
+	// Chinese 中文（默认）
+	Chinese Language = "zh"
+	// English 英文
+	English Language = "en"
+)
+
+// catalogs 各语言的消息表，键为中文文本
+var catalogs = map[Language]map[string]string{
+	English: english,
+}
+
+// current 命令行消息使用的语言
+var current = Chinese
+
+// Parse 根据字符串返回对应的语言，空字符串表示中文，
+// 支持zh-CN、en_US等带地区的写法
+func Parse(lang string) (Language, error) {
+	normalized := strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(normalized, "-_"); i >= 0 {
+		normalized = normalized[:i]
+	}
+
+	switch Language(normalized) {
+	case "", Chinese:
+		return Chinese, nil
+	case English:
+		return English, nil
+	default:
+		return "", fmt.Errorf("不支持的语言: %s (可选: zh, en)", lang)
+	}
+}
+
+// Languages 返回支持的语言
+func Languages() []Language {
+	return []Language{Chinese, English}
+}
+
+// SetLanguage 设置命令行消息使用的语言
+func SetLanguage(lang Language) {
+	current = lang
+}
+
+// Current 返回命令行消息使用的语言
+func Current() Language {
+	return current
+}
+
+// Translate 返回文本在指定语言中的翻译，没有翻译时返回原文
+func Translate(lang Language, text string) string {
+	if translated, ok := catalogs[lang][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// T 返回文本在当前语言中的翻译
+func T(text string) string {
+	return Translate(current, text)
+}
+
+// Printf 使用当前语言的格式字符串打印消息。
+// 进度、提示和错误消息输出到标准错误，标准输出只留给报告等数据，便于重定向和管道处理
+func Printf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, T(format), args...)
+}
+
+// Println 使用当前语言打印一行消息到标准错误
+func Println(text string) {
+	fmt.Fprintln(os.Stderr, T(text))
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestParse 测试语言解析
+func TestParse(t *testing.T) {
+	tests := map[string]Language{"": Chinese, "zh": Chinese, "zh-CN": Chinese, "EN": English, "en_US": English}
+	for input, expected := range tests {
+		if lang, err := Parse(input); err != nil || lang != expected {
+			t.Errorf("Parse(%q) 期望 %s, 得到: %s (err: %v)", input, expected, lang, err)
+		}
+	}
+	if _, err := Parse("fr"); err == nil {
+		t.Error("不支持的语言应该返回错误")
+	}
+}
+
+// TestTranslate 测试翻译和没有翻译时返回原文
+func TestTranslate(t *testing.T) {
+	if got := Translate(English, "## 提交记录"); got != "## Commits" {
+		t.Errorf("翻译不正确: %s", got)
+	}
+	if got := Translate(Chinese, "## 提交记录"); got != "## 提交记录" {
+		t.Errorf("中文应返回原文: %s", got)
+	}
+	if got := Translate(English, "没有翻译的文本"); got != "没有翻译的文本" {
+		t.Errorf("没有翻译时应返回原文: %s", got)
+	}
+}
+
+// TestCatalogVerbs 测试翻译的格式化动词与原文一致，避免输出%!d(string=...)之类的错误
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for source, translated := range catalog {
+			args := sampleArgs(source)
+			expected := fmt.Sprintf(source, args...)
+			got := fmt.Sprintf(translated, args...)
+			if strings.Contains(expected, "%!") || strings.Contains(got, "%!") {
+				t.Errorf("[%s] 格式化动词不一致: %q -> %q (%s)", lang, source, translated, got)
+			}
+		}
+	}
+}
+
+// sampleArgs 根据格式字符串中的动词生成示例参数
+func sampleArgs(format string) []any {
+	var args []any
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("-+# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+		switch format[i] {
+		case '%':
+		case 'd':
+			args = append(args, 1)
+		default:
+			args = append(args, "x")
+		}
+	}
+	return args
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// utf8BOM 写在CSV开头，让Excel等表格软件按UTF-8识别中文
+const utf8BOM = "\ufeff"
+
+// generateCSVReport 生成CSV格式的提交明细，每个提交一行，便于导入表格填写工时。
+// 设置了DailyOutput时，同时输出按日期和仓库汇总的表格
+func (g *Generator) generateCSVReport(commits []git.CommitInfo) error {
+	sorted := make([]git.CommitInfo, len(commits))
+	copy(sorted, commits)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	header := []string{"日期", "仓库", "分支", "作者", "哈希值", "标题", "变更文件数", "新增行数", "删除行数", "角色"}
+	rows := make([][]string, 0, len(sorted))
+	for _, commit := range sorted {
+		rows = append(rows, []string{
+			commit.Date.Format("2006-01-02 15:04:05"),
+			commit.RepoPath,
+			strings.Join(commit.Branches, ", "),
+			commit.Author,
+			commit.Hash,
+			strings.TrimSpace(strings.SplitN(commit.Message, "\n", 2)[0]),
+			strconv.Itoa(len(commit.ChangedFiles)),
+			strconv.Itoa(commit.Insertions),
+			strconv.Itoa(commit.Deletions),
+			g.t(roleLabel(commit.Role)),
+		})
+	}
+	if err := g.writeCSV(g.Output, header, rows); err != nil {
+		return fmt.Errorf("输出CSV报告失败: %w", err)
+	}
+
+	if g.DailyOutput != nil {
+		if err := g.writeCSV(g.DailyOutput, dailyHeader, dailyRows(sorted)); err != nil {
+			return fmt.Errorf("输出按天汇总的CSV失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// dailyHeader 按天汇总表格的表头
+var dailyHeader = []string{"日期", "仓库", "提交数", "变更文件数", "新增行数", "删除行数", "首次提交", "最后提交"}
+
+// dailyStats 某一天在某个仓库的工作量
+type dailyStats struct {
+	date       string
+	repo       string
+	commits    int
+	files      map[string]bool
+	insertions int
+	deletions  int
+	first      time.Time
+	last       time.Time
+}
+
+// dailyRows 按日期和仓库汇总提交，commits需要已按时间排序
+func dailyRows(commits []git.CommitInfo) [][]string {
+	var days []*dailyStats
+	index := make(map[string]*dailyStats)
+	for _, commit := range commits {
+		date := commit.Date.Format("2006-01-02")
+		key := date + "\x00" + commit.RepoPath
+		day, ok := index[key]
+		if !ok {
+			day = &dailyStats{date: date, repo: commit.RepoPath, files: make(map[string]bool), first: commit.Date}
+			index[key] = day
+			days = append(days, day)
+		}
+
+		day.commits++
+		for _, file := range commit.ChangedFiles {
+			day.files[file] = true
+		}
+		day.insertions += commit.Insertions
+		day.deletions += commit.Deletions
+		day.last = commit.Date
+	}
+
+	sort.SliceStable(days, func(i, j int) bool {
+		if days[i].date != days[j].date {
+			return days[i].date < days[j].date
+		}
+		return days[i].repo < days[j].repo
+	})
+
+	rows := make([][]string, 0, len(days))
+	for _, day := range days {
+		rows = append(rows, []string{
+			day.date,
+			day.repo,
+			strconv.Itoa(day.commits),
+			strconv.Itoa(len(day.files)),
+			strconv.Itoa(day.insertions),
+			strconv.Itoa(day.deletions),
+			day.first.Format("15:04"),
+			day.last.Format("15:04"),
+		})
+	}
+	return rows
+}
+
+// writeCSV 写入带UTF-8 BOM和翻译后表头的CSV
+func (g *Generator) writeCSV(w io.Writer, header []string, rows [][]string) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	translated := make([]string, len(header))
+	for i, column := range header {
+		translated[i] = g.t(column)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(translated); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		escaped := make([]string, len(row))
+		for i, cell := range row {
+			escaped[i] = escapeFormula(cell)
+		}
+		if err := writer.Write(escaped); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeFormula 在以=、+、-、@开头的单元格前加上单引号，
+// 避免提交标题、作者名等内容在Excel中被当作公式执行
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
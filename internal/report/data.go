@@ -0,0 +1,151 @@
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/internal/structured"
+)
+
+// Report 报告的数据模型，JSON格式直接输出该结构
+type Report struct {
+	Title        string              `json:"title"`                // 报告标题，如"工作周报"
+	Type         string              `json:"type"`                 // 报告类型：daily、weekly、monthly、yearly或report
+	From         time.Time           `json:"from"`                 // 开始时间
+	To           time.Time           `json:"to"`                   // 结束时间
+	Language     string              `json:"language"`             // 报告语言
+	Summary      string              `json:"summary"`              // AI总结
+	Structured   *structured.Summary `json:"structured,omitempty"` // 结构化的AI总结
+	TotalCommits int                 `json:"total_commits"`        // 提交总数
+	Repos        []RepoStats         `json:"repos"`                // 每个仓库的统计
+	Commits      []git.CommitInfo    `json:"commits"`              // 提交记录
+}
+
+// RepoStats 单个仓库的统计信息
+type RepoStats struct {
+	Repo         string `json:"repo"`          // 仓库路径
+	Commits      int    `json:"commits"`       // 提交数量
+	Authors      int    `json:"authors"`       // 提交作者数量
+	ChangedFiles int    `json:"changed_files"` // 变更过的文件数量，同一文件只计一次
+	Insertions   int    `json:"insertions"`    // 新增的行数
+	Deletions    int    `json:"deletions"`     // 删除的行数
+}
+
+// CommitGroup 同一仓库的提交记录
+type CommitGroup struct {
+	Repo    string           // 仓库路径
+	Commits []git.CommitInfo // 按原始顺序排列的提交记录
+}
+
+// newReport 根据AI总结和提交记录创建报告的数据模型
+func (g *Generator) newReport(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) Report {
+	language := g.Language
+	if language == "" {
+		language = i18n.Chinese
+	}
+
+	// 复制提交记录，空的分支和文件列表输出为[]而不是null
+	reportCommits := make([]git.CommitInfo, len(commits))
+	for i, commit := range commits {
+		if commit.Branches == nil {
+			commit.Branches = []string{}
+		}
+		if commit.ChangedFiles == nil {
+			commit.ChangedFiles = []string{}
+		}
+		if commit.FileStats == nil {
+			commit.FileStats = []git.FileStat{}
+		}
+		if commit.CoAuthors == nil {
+			commit.CoAuthors = []git.CoAuthor{}
+		}
+		reportCommits[i] = commit
+	}
+
+	return Report{
+		Title:        g.t(g.determineReportType(fromDate, toDate)),
+		Type:         g.getReportTypeShort(fromDate, toDate),
+		From:         fromDate,
+		To:           toDate,
+		Language:     string(language),
+		Summary:      summary,
+		Structured:   g.Structured,
+		TotalCommits: len(commits),
+		Repos:        repoStats(commits),
+		Commits:      reportCommits,
+	}
+}
+
+// repoStats 按仓库统计提交、作者和变更文件数量，按仓库路径排序
+func repoStats(commits []git.CommitInfo) []RepoStats {
+	stats := make([]RepoStats, 0)
+	for _, group := range groupByRepo(commits) {
+		if group.Repo == "" {
+			continue
+		}
+
+		authors := make(map[string]bool)
+		files := make(map[string]bool)
+		insertions, deletions := 0, 0
+		for _, commit := range group.Commits {
+			authors[commit.Author] = true
+			insertions += commit.Insertions
+			deletions += commit.Deletions
+			for _, file := range commit.ChangedFiles {
+				files[file] = true
+			}
+		}
+		stats = append(stats, RepoStats{
+			Repo:         group.Repo,
+			Commits:      len(group.Commits),
+			Authors:      len(authors),
+			ChangedFiles: len(files),
+			Insertions:   insertions,
+			Deletions:    deletions,
+		})
+	}
+	return stats
+}
+
+// roleLabel 返回提交角色的显示名称，没有按作者筛选时为空
+func roleLabel(role git.CommitRole) string {
+	switch role {
+	case git.RoleAuthor:
+		return "作者"
+	case git.RoleCoAuthor:
+		return "共同作者"
+	default:
+		return ""
+	}
+}
+
+// coAuthorNames 返回共同作者的名称列表，没有名称时使用邮箱
+func coAuthorNames(coAuthors []git.CoAuthor) string {
+	names := make([]string, 0, len(coAuthors))
+	for _, coAuthor := range coAuthors {
+		if coAuthor.Name != "" {
+			names = append(names, coAuthor.Name)
+		} else {
+			names = append(names, coAuthor.Email)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// groupByRepo 按仓库对提交记录分组，按仓库路径排序
+func groupByRepo(commits []git.CommitInfo) []CommitGroup {
+	groups := make(map[string][]git.CommitInfo)
+	for _, commit := range commits {
+		groups[commit.RepoPath] = append(groups[commit.RepoPath], commit)
+	}
+
+	result := make([]CommitGroup, 0, len(groups))
+	for repo, repoCommits := range groups {
+		result = append(result, CommitGroup{Repo: repo, Commits: repoCommits})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Repo < result[j].Repo })
+	return result
+}
@@ -0,0 +1,22 @@
+package report
+
+import (
+	_ "embed"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// htmlTemplate HTML报告的页面模板，样式内联在页面中，不依赖任何外部资源
+//
+//go:embed html.tmpl
+var htmlTemplate string
+
+// generateHTMLReport 生成单文件的HTML报告，可以直接用浏览器打开或作为邮件附件
+func (g *Generator) generateHTMLReport(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) error {
+	tmpl, err := ParseTemplate("report.html.tmpl", htmlTemplate)
+	if err != nil {
+		return err
+	}
+	return g.generateTemplateReport(tmpl, summary, commits, fromDate, toDate)
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingPattern 匹配Markdown标题，如 ## 主要成果
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	// listItemPattern 匹配有序和无序列表项，第一组为缩进
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	// rulePattern 匹配分隔线
+	rulePattern = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	// boldPattern 匹配加粗文本
+	boldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	// italicPattern 匹配斜体文本
+	italicPattern = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	// linkPattern 匹配http和https链接
+	linkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+)
+
+// markdownList 正在输出的一层列表
+type markdownList struct {
+	indent int    // 列表项的缩进
+	tag    string // ul或ol
+}
+
+// markdownRenderer 按行把Markdown转换为HTML
+type markdownRenderer struct {
+	out       strings.Builder
+	paragraph []string
+	lists     []markdownList
+	inCode    bool
+}
+
+// renderMarkdown 将AI总结中常用的Markdown语法转换为HTML，支持标题、可嵌套的列表、
+// 代码块、分隔线、段落以及加粗、斜体、行内代码和链接，其他语法按普通文本输出。
+// 所有文本都会先做HTML转义，AI返回的内容不会注入任意HTML
+func renderMarkdown(text string) template.HTML {
+	r := &markdownRenderer{}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		r.renderLine(strings.ReplaceAll(line, "\t", "    "))
+	}
+
+	if r.inCode {
+		r.out.WriteString("</code></pre>\n")
+	}
+	r.flushParagraph()
+	r.closeLists(-1)
+	return template.HTML(r.out.String())
+}
+
+// renderLine 处理一行Markdown
+func (r *markdownRenderer) renderLine(line string) {
+	trimmed := strings.TrimSpace(line)
+
+	// 代码块内的内容原样输出
+	if r.inCode {
+		if strings.HasPrefix(trimmed, "```") {
+			r.out.WriteString("</code></pre>\n")
+			r.inCode = false
+			return
+		}
+		r.out.WriteString(html.EscapeString(line) + "\n")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(trimmed, "```"):
+		r.flushParagraph()
+		r.closeLists(-1)
+		r.out.WriteString("<pre><code>")
+		r.inCode = true
+	case trimmed == "":
+		// 空行结束段落，列表之间的空行不结束列表
+		r.flushParagraph()
+	case headingPattern.MatchString(trimmed):
+		r.flushParagraph()
+		r.closeLists(-1)
+		matches := headingPattern.FindStringSubmatch(trimmed)
+		level := string(rune('0' + len(matches[1])))
+		r.out.WriteString("<h" + level + ">" + renderInline(matches[2]) + "</h" + level + ">\n")
+	case rulePattern.MatchString(trimmed):
+		r.flushParagraph()
+		r.closeLists(-1)
+		r.out.WriteString("<hr>\n")
+	case listItemPattern.MatchString(line):
+		r.flushParagraph()
+		matches := listItemPattern.FindStringSubmatch(line)
+		tag := "ul"
+		if matches[2][0] >= '0' && matches[2][0] <= '9' {
+			tag = "ol"
+		}
+		r.listItem(len(matches[1]), tag, matches[3])
+	case len(r.lists) > 0 && line != trimmed && len(r.paragraph) == 0:
+		// 缩进的行是上一个列表项的延续
+		r.out.WriteString("<br>" + renderInline(trimmed))
+	default:
+		r.closeLists(-1)
+		r.paragraph = append(r.paragraph, trimmed)
+	}
+}
+
+// listItem 输出一个列表项，缩进更深的列表项嵌套在上一个列表项中
+func (r *markdownRenderer) listItem(indent int, tag, content string) {
+	r.closeLists(indent)
+
+	top := len(r.lists) - 1
+	switch {
+	case top >= 0 && r.lists[top].indent == indent && r.lists[top].tag == tag:
+		r.out.WriteString("</li>\n<li>")
+	case top >= 0 && r.lists[top].indent == indent:
+		// 同一层级换了列表类型
+		r.closeList()
+		r.openList(indent, tag)
+	default:
+		r.openList(indent, tag)
+	}
+	r.out.WriteString(renderInline(content))
+}
+
+// openList 开始一层新的列表
+func (r *markdownRenderer) openList(indent int, tag string) {
+	r.lists = append(r.lists, markdownList{indent: indent, tag: tag})
+	r.out.WriteString("<" + tag + ">\n<li>")
+}
+
+// closeList 结束最内层的列表
+func (r *markdownRenderer) closeList() {
+	top := r.lists[len(r.lists)-1]
+	r.lists = r.lists[:len(r.lists)-1]
+	r.out.WriteString("</li>\n</" + top.tag + ">\n")
+}
+
+// closeLists 结束缩进大于indent的所有列表，indent为-1时结束全部列表
+func (r *markdownRenderer) closeLists(indent int) {
+	for len(r.lists) > 0 && (indent < 0 || r.lists[len(r.lists)-1].indent > indent) {
+		r.closeList()
+	}
+}
+
+// flushParagraph 输出缓存的段落，段落内的换行保留为<br>
+func (r *markdownRenderer) flushParagraph() {
+	if len(r.paragraph) == 0 {
+		return
+	}
+
+	lines := make([]string, len(r.paragraph))
+	for i, line := range r.paragraph {
+		lines[i] = renderInline(line)
+	}
+	r.out.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	r.paragraph = nil
+}
+
+// renderInline 转义文本并转换行内代码、加粗、斜体和链接
+func renderInline(text string) string {
+	var result strings.Builder
+
+	// 反引号之间的内容是行内代码，不再处理其中的其他语法
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		closed := i%2 == 1 && (len(parts)%2 == 1 || i < len(parts)-1)
+		switch {
+		case closed:
+			result.WriteString("<code>" + escaped + "</code>")
+		case i%2 == 1:
+			// 没有配对的反引号按普通字符输出
+			result.WriteString("`" + renderEmphasis(escaped))
+		default:
+			result.WriteString(renderEmphasis(escaped))
+		}
+	}
+	return result.String()
+}
+
+// renderEmphasis 转换已转义文本中的加粗、斜体和链接
+func renderEmphasis(escaped string) string {
+	escaped = linkPattern.ReplaceAllString(escaped, `<a href="$2">$1</a>`)
+	escaped = boldPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	return italicPattern.ReplaceAllString(escaped, "<em>$1</em>")
+}
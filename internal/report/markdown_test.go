@@ -0,0 +1,43 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRenderMarkdown 测试AI总结中常用Markdown语法的转换
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"标题", "### 主要成果", "<h3>主要成果</h3>\n"},
+		{"段落保留换行", "第一行\n第二行", "<p>第一行<br>\n第二行</p>\n"},
+		{"行内语法", "**周报** 和 *导出* 见 `report.go`", "<p><strong>周报</strong> 和 <em>导出</em> 见 <code>report.go</code></p>\n"},
+		{"链接", "[文档](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2">文档</a></p>` + "\n"},
+		{"转义HTML", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"嵌套列表", "- 周报\n  - 导出\n- 修复", "<ul>\n<li>周报<ul>\n<li>导出</li>\n</ul>\n</li>\n<li>修复</li>\n</ul>\n"},
+		{"有序列表", "1. 第一\n2. 第二", "<ol>\n<li>第一</li>\n<li>第二</li>\n</ol>\n"},
+		{"代码块", "```\na < b\n```", "<pre><code>a &lt; b\n</code></pre>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(renderMarkdown(tt.markdown)); got != tt.want {
+				t.Errorf("renderMarkdown(%q)\n得到: %q\n期望: %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderMarkdownUnclosed 测试未闭合的语法不会破坏HTML结构
+func TestRenderMarkdownUnclosed(t *testing.T) {
+	got := string(renderMarkdown("- 列表\n```\n代码"))
+	if !strings.HasSuffix(got, "</code></pre>\n") || strings.Count(got, "<ul>") != strings.Count(got, "</ul>") {
+		t.Errorf("未闭合的列表和代码块应自动闭合, 得到: %q", got)
+	}
+	if got := string(renderMarkdown("单个`反引号")); got != "<p>单个`反引号</p>\n" {
+		t.Errorf("没有配对的反引号应原样输出, 得到: %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package report
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -8,6 +9,8 @@ import (
 	"time"
 
 	"github.com/kway-teow/git-work-log/internal/git"
+	"github.com/kway-teow/git-work-log/internal/i18n"
+	"github.com/kway-teow/git-work-log/internal/structured"
 )
 
 // Format 表示报告输出格式
@@ -18,12 +21,27 @@ const (
 	FormatText Format = "text"
 	// FormatMarkdown Markdown格式
 	FormatMarkdown Format = "markdown"
+	// FormatJSON JSON格式，便于其他程序和仪表盘读取
+	FormatJSON Format = "json"
+	// FormatHTML 单文件HTML格式，样式内联，可以直接用浏览器打开
+	FormatHTML Format = "html"
+	// FormatCSV CSV格式，每个提交一行，便于导入表格
+	FormatCSV Format = "csv"
 )
 
+// UsesSummary 返回该格式的报告是否包含AI总结，CSV格式只输出提交明细
+func (f Format) UsesSummary() bool {
+	return f != FormatCSV
+}
+
 // Generator 报告生成器
 type Generator struct {
-	Format Format
-	Output io.Writer // 输出目标，可以是文件或标准输出
+	Format      Format
+	Output      io.Writer           // 输出目标，可以是文件或标准输出
+	Structured  *structured.Summary // 结构化摘要，不为nil时按栏目渲染AI总结
+	Language    i18n.Language       // 报告语言，为空时使用中文
+	Template    *Template           // 自定义报告模板，不为nil时忽略Format
+	DailyOutput io.Writer           // CSV格式时按日期和仓库汇总的表格的输出目标，为nil时不输出
 }
 
 // NewGenerator 创建一个新的报告生成器
@@ -37,10 +55,20 @@ func NewGenerator(format Format, output io.Writer) *Generator {
 
 // GenerateReport 生成周报
 func (g *Generator) GenerateReport(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) error {
+	if g.Template != nil {
+		return g.generateTemplateReport(g.Template, summary, commits, fromDate, toDate)
+	}
+
 	// 根据格式生成报告
 	switch g.Format {
 	case FormatMarkdown:
 		return g.generateMarkdownReport(summary, commits, fromDate, toDate)
+	case FormatJSON:
+		return g.generateJSONReport(summary, commits, fromDate, toDate)
+	case FormatHTML:
+		return g.generateHTMLReport(summary, commits, fromDate, toDate)
+	case FormatCSV:
+		return g.generateCSVReport(commits)
 	default: // 默认使用文本格式
 		return g.generateTextReport(summary, commits, fromDate, toDate)
 	}
@@ -49,9 +77,9 @@ func (g *Generator) GenerateReport(summary string, commits []git.CommitInfo, fro
 // generateTextReport 生成纯文本格式的报告
 func (g *Generator) generateTextReport(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) error {
 	// 根据时间范围确定报告类型
-	reportType := g.determineReportType(fromDate, toDate)
+	reportType := g.t(g.determineReportType(fromDate, toDate))
 
-	fmt.Fprintf(g.Output, "%s (%s 至 %s)\n", reportType, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
+	fmt.Fprintf(g.Output, g.t("%s (%s 至 %s)\n"), reportType, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
 	fmt.Fprintln(g.Output, "==================================")
 	fmt.Fprintln(g.Output)
 
@@ -65,36 +93,47 @@ func (g *Generator) generateTextReport(summary string, commits []git.CommitInfo,
 
 	// 如果有多个仓库，显示仓库统计
 	if len(repoStats) > 1 {
-		fmt.Fprintln(g.Output, "## 仓库统计")
+		fmt.Fprintln(g.Output, g.t("## 仓库统计"))
 		for repo, count := range repoStats {
-			fmt.Fprintf(g.Output, "- %s: %d 条提交\n", repo, count)
+			fmt.Fprintf(g.Output, g.t("- %s: %d 条提交\n"), repo, count)
 		}
 		fmt.Fprintln(g.Output)
 	}
 
-	fmt.Fprintln(g.Output, "## AI 总结")
-	fmt.Fprintln(g.Output, summary)
+	fmt.Fprintln(g.Output, g.t("## AI 总结"))
+	if g.Structured != nil {
+		g.writeStructuredText(g.Output, g.Structured)
+	} else {
+		fmt.Fprintln(g.Output, summary)
+	}
 	fmt.Fprintln(g.Output)
-	fmt.Fprintln(g.Output, "## 提交记录")
-	fmt.Fprintf(g.Output, "共有 %d 条提交记录\n\n", len(commits))
+	fmt.Fprintln(g.Output, g.t("## 提交记录"))
+	fmt.Fprintf(g.Output, g.t("共有 %d 条提交记录\n\n"), len(commits))
 
 	for i, commit := range commits {
-		fmt.Fprintf(g.Output, "提交 %d:\n", i+1)
-		fmt.Fprintf(g.Output, "- 哈希值: %s\n", commit.Hash[:8])
-		fmt.Fprintf(g.Output, "- 作者: %s\n", commit.Author)
-		fmt.Fprintf(g.Output, "- 日期: %s\n", commit.Date.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(g.Output, g.t("提交 %d:\n"), i+1)
+		fmt.Fprintf(g.Output, g.t("- 哈希值: %s\n"), commit.Hash[:8])
+		fmt.Fprintf(g.Output, g.t("- 作者: %s\n"), commit.Author)
+		if len(commit.CoAuthors) > 0 {
+			fmt.Fprintf(g.Output, g.t("- 共同作者: %s\n"), coAuthorNames(commit.CoAuthors))
+		}
+		// 作为共同作者参与的提交标出角色
+		if commit.Role == git.RoleCoAuthor {
+			fmt.Fprintf(g.Output, g.t("- 角色: %s\n"), g.t(roleLabel(commit.Role)))
+		}
+		fmt.Fprintf(g.Output, g.t("- 日期: %s\n"), commit.Date.Format("2006-01-02 15:04:05"))
 
 		// 显示仓库信息（如果有多个仓库）
 		if len(repoStats) > 1 && commit.RepoPath != "" {
-			fmt.Fprintf(g.Output, "- 仓库: %s\n", commit.RepoPath)
+			fmt.Fprintf(g.Output, g.t("- 仓库: %s\n"), commit.RepoPath)
 		}
 
 		// 显示分支信息
 		if len(commit.Branches) > 0 {
-			fmt.Fprintf(g.Output, "- 分支: %s\n", strings.Join(commit.Branches, ", "))
+			fmt.Fprintf(g.Output, g.t("- 分支: %s\n"), strings.Join(commit.Branches, ", "))
 		}
 
-		fmt.Fprintf(g.Output, "- 消息: %s\n\n", commit.Message)
+		fmt.Fprintf(g.Output, g.t("- 消息: %s\n\n"), commit.Message)
 	}
 
 	return nil
@@ -103,7 +142,7 @@ func (g *Generator) generateTextReport(summary string, commits []git.CommitInfo,
 // generateMarkdownReport 生成Markdown格式的报告
 func (g *Generator) generateMarkdownReport(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) error {
 	// 根据时间范围确定报告类型
-	reportType := g.determineReportType(fromDate, toDate)
+	reportType := g.t(g.determineReportType(fromDate, toDate))
 
 	// 生成文件名用于提示
 	fileName := fmt.Sprintf("%s-%s-to-%s.md",
@@ -112,7 +151,7 @@ func (g *Generator) generateMarkdownReport(summary string, commits []git.CommitI
 		toDate.Format("2006-01-02"))
 
 	// 写入标题
-	fmt.Fprintf(g.Output, "# %s (%s 至 %s)\n\n",
+	fmt.Fprintf(g.Output, g.t("# %s (%s 至 %s)\n\n"),
 		reportType,
 		fromDate.Format("2006-01-02"),
 		toDate.Format("2006-01-02"))
@@ -127,43 +166,69 @@ func (g *Generator) generateMarkdownReport(summary string, commits []git.CommitI
 
 	// 如果有多个仓库，显示仓库统计
 	if len(repoStats) > 1 {
-		fmt.Fprintln(g.Output, "## 仓库统计")
+		fmt.Fprintln(g.Output, g.t("## 仓库统计"))
 		fmt.Fprintln(g.Output)
 		for repo, count := range repoStats {
-			fmt.Fprintf(g.Output, "- **%s**: %d 条提交\n", repo, count)
+			fmt.Fprintf(g.Output, g.t("- **%s**: %d 条提交\n"), repo, count)
 		}
 		fmt.Fprintln(g.Output)
 	}
 
 	// 写入AI总结
-	fmt.Fprintln(g.Output, "## AI 总结")
-	fmt.Fprintln(g.Output, summary)
+	fmt.Fprintln(g.Output, g.t("## AI 总结"))
+	if g.Structured != nil {
+		fmt.Fprintln(g.Output)
+		g.writeStructuredMarkdown(g.Output, g.Structured)
+	} else {
+		fmt.Fprintln(g.Output, summary)
+	}
 	fmt.Fprintln(g.Output)
 
 	// 写入提交记录
-	fmt.Fprintln(g.Output, "## 提交记录")
-	fmt.Fprintf(g.Output, "共有 %d 条提交记录\n\n", len(commits))
+	fmt.Fprintln(g.Output, g.t("## 提交记录"))
+	fmt.Fprintf(g.Output, g.t("共有 %d 条提交记录\n\n"), len(commits))
 
 	for i, commit := range commits {
-		fmt.Fprintf(g.Output, "### 提交 %d\n\n", i+1)
-		fmt.Fprintf(g.Output, "- **哈希值**: `%s`\n", commit.Hash[:8])
-		fmt.Fprintf(g.Output, "- **作者**: %s\n", commit.Author)
-		fmt.Fprintf(g.Output, "- **日期**: %s\n", commit.Date.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(g.Output, g.t("### 提交 %d\n\n"), i+1)
+		fmt.Fprintf(g.Output, g.t("- **哈希值**: `%s`\n"), commit.Hash[:8])
+		fmt.Fprintf(g.Output, g.t("- **作者**: %s\n"), commit.Author)
+		if len(commit.CoAuthors) > 0 {
+			fmt.Fprintf(g.Output, g.t("- **共同作者**: %s\n"), coAuthorNames(commit.CoAuthors))
+		}
+		// 作为共同作者参与的提交标出角色
+		if commit.Role == git.RoleCoAuthor {
+			fmt.Fprintf(g.Output, g.t("- **角色**: %s\n"), g.t(roleLabel(commit.Role)))
+		}
+		fmt.Fprintf(g.Output, g.t("- **日期**: %s\n"), commit.Date.Format("2006-01-02 15:04:05"))
 
 		// 显示仓库信息（如果有多个仓库）
 		if len(repoStats) > 1 && commit.RepoPath != "" {
-			fmt.Fprintf(g.Output, "- **仓库**: `%s`\n", commit.RepoPath)
+			fmt.Fprintf(g.Output, g.t("- **仓库**: `%s`\n"), commit.RepoPath)
 		}
 
 		// 显示分支信息
 		if len(commit.Branches) > 0 {
-			fmt.Fprintf(g.Output, "- **分支**: %s\n", strings.Join(commit.Branches, ", "))
+			fmt.Fprintf(g.Output, g.t("- **分支**: %s\n"), strings.Join(commit.Branches, ", "))
 		}
 
-		fmt.Fprintf(g.Output, "- **消息**: %s\n", commit.Message)
+		fmt.Fprintf(g.Output, g.t("- **消息**: %s\n"), commit.Message)
+
+		// 提交正文作为引用块输出
+		if commit.Body != "" {
+			fmt.Fprintln(g.Output, g.t("- **说明**:"))
+			fmt.Fprintln(g.Output)
+			for _, line := range strings.Split(commit.Body, "\n") {
+				fmt.Fprintf(g.Output, "  > %s\n", line)
+			}
+			fmt.Fprintln(g.Output)
+		}
+
+		if commit.Insertions > 0 || commit.Deletions > 0 {
+			fmt.Fprintf(g.Output, g.t("- **变更行数**: +%d -%d\n"), commit.Insertions, commit.Deletions)
+		}
 
 		if len(commit.ChangedFiles) > 0 {
-			fmt.Fprintln(g.Output, "- **变更文件**:")
+			fmt.Fprintln(g.Output, g.t("- **变更文件**:"))
 			for _, fileName := range commit.ChangedFiles {
 				fmt.Fprintf(g.Output, "  - `%s`\n", fileName)
 			}
@@ -174,11 +239,47 @@ func (g *Generator) generateMarkdownReport(summary string, commits []git.CommitI
 
 	// 如果输出不是标准输出，打印提示信息
 	if g.Output != os.Stdout {
-		fmt.Printf("已生成Markdown报告: %s\n", fileName)
+		fmt.Fprintf(os.Stderr, g.t("已生成Markdown报告: %s\n"), fileName)
 	}
 	return nil
 }
 
+// generateJSONReport 生成JSON格式的报告
+func (g *Generator) generateJSONReport(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) error {
+	encoder := json.NewEncoder(g.Output)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(g.newReport(summary, commits, fromDate, toDate)); err != nil {
+		return fmt.Errorf("输出JSON报告失败: %w", err)
+	}
+	return nil
+}
+
+// writeStructuredText 以纯文本格式输出结构化摘要
+func (g *Generator) writeStructuredText(w io.Writer, summary *structured.Summary) {
+	fmt.Fprintf(w, g.t("总体概述: %s\n"), summary.Overview)
+	for _, section := range summary.Sections() {
+		fmt.Fprintf(w, "\n%s:\n", g.t(section.Title))
+		if len(section.Items) == 0 {
+			fmt.Fprintln(w, g.t("- 无"))
+			continue
+		}
+		for _, item := range section.Items {
+			fmt.Fprintf(w, "- %s\n", item)
+		}
+	}
+}
+
+// writeStructuredMarkdown 以Markdown格式输出结构化摘要
+func (g *Generator) writeStructuredMarkdown(w io.Writer, summary *structured.Summary) {
+	fmt.Fprint(w, summary.Markdown(g.Language))
+}
+
+// t 返回文本在报告语言中的翻译
+func (g *Generator) t(text string) string {
+	return i18n.Translate(g.Language, text)
+}
+
 // determineReportType 根据时间范围确定报告类型
 func (g *Generator) determineReportType(fromDate, toDate time.Time) string {
 	// 计算时间范围的天数
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// testCommits 测试用的提交记录
+func testCommits() []git.CommitInfo {
+	return []git.CommitInfo{
+		{
+			Hash:         "abcdef1234567890",
+			Author:       "John Doe",
+			Date:         time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC),
+			Message:      "feat: 添加周报功能",
+			Branches:     []string{"main"},
+			ChangedFiles: []string{"report.go"},
+			RepoPath:     "web",
+		},
+		{
+			Hash:     "1234567890abcdef",
+			Author:   "Jane Doe",
+			Date:     time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC),
+			Message:  "fix: 修复导出",
+			RepoPath: "api",
+		},
+	}
+}
+
+// TestGenerateJSONReport 测试JSON格式报告的结构
+func TestGenerateJSONReport(t *testing.T) {
+	var output bytes.Buffer
+	generator := NewGenerator(FormatJSON, &output)
+	from := time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC)
+
+	if err := generator.GenerateReport("本周完成了周报功能", testCommits(), from, to); err != nil {
+		t.Fatalf("生成报告失败: %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("报告不是合法的JSON: %v\n%s", err, output.String())
+	}
+
+	if report.Title != "工作周报" || report.Type != "weekly" || report.Summary != "本周完成了周报功能" || !report.From.Equal(from) {
+		t.Errorf("报告信息不正确: %+v", report)
+	}
+	if report.TotalCommits != 2 || len(report.Repos) != 2 || report.Repos[0] != (RepoStats{Repo: "api", Commits: 1, Authors: 1}) {
+		t.Errorf("仓库统计不正确: %+v", report.Repos)
+	}
+	if len(report.Commits) != 2 || report.Commits[0].ChangedFiles[0] != "report.go" || report.Commits[1].Branches == nil {
+		t.Errorf("提交记录不正确: %+v", report.Commits)
+	}
+
+	// 字段名使用蛇形命名
+	var raw map[string]any
+	_ = json.Unmarshal(output.Bytes(), &raw)
+	commit := raw["commits"].([]any)[0].(map[string]any)
+	for _, key := range []string{"hash", "author", "date", "message", "branches", "changed_files", "repo"} {
+		if _, ok := commit[key]; !ok {
+			t.Errorf("提交记录缺少字段 %s: %v", key, commit)
+		}
+	}
+}
+
+// TestGenerateHTMLReport 测试HTML报告包含渲染后的总结、仓库统计和按仓库分组的提交
+func TestGenerateHTMLReport(t *testing.T) {
+	var output bytes.Buffer
+	generator := NewGenerator(FormatHTML, &output)
+	from := time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC)
+
+	commits := testCommits()
+	commits[1].Message = "fix: 修复<导出>"
+	commits[1].Role = git.RoleCoAuthor
+	commits[1].CoAuthors = []git.CoAuthor{{Name: "John Doe", Email: "john@example.com"}}
+	if err := generator.GenerateReport("## 本周\n- **周报**功能", commits, from, to); err != nil {
+		t.Fatalf("生成报告失败: %v", err)
+	}
+
+	html := output.String()
+	for _, want := range []string{
+		"<title>工作周报 (2025-05-19 至 2025-05-25)</title>",
+		"<h2>本周</h2>",
+		"<li><strong>周报</strong>功能</li>",
+		`<details class="repo" open>`,
+		"<summary>api <span class=\"count\">(1 条提交)</span></summary>",
+		"修复&lt;导出&gt;",
+		`<code title="abcdef1234567890">abcdef12</code>`,
+		`Jane Doe <span class="role">共同作者</span><div class="coauthors">+ John Doe</div>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML报告缺少 %q", want)
+		}
+	}
+	if strings.Index(html, "<summary>api") > strings.Index(html, "<summary>web") {
+		t.Error("仓库分组应按名称排序")
+	}
+	if strings.Contains(html, "<link") || strings.Contains(html, "<script") {
+		t.Error("HTML报告不应引用外部资源")
+	}
+}
+
+// TestGenerateCSVReport 测试CSV提交明细和按天汇总的表格
+func TestGenerateCSVReport(t *testing.T) {
+	var output, daily bytes.Buffer
+	generator := NewGenerator(FormatCSV, &output)
+	generator.DailyOutput = &daily
+
+	commits := testCommits()
+	commits[0].Message = "feat: 添加周报, 支持\"导出\"\n\n详细说明"
+	commits[0].Insertions, commits[0].Deletions = 10, 2
+	commits[0].Role = git.RoleAuthor
+	commits[1].Role = git.RoleCoAuthor
+	commits = append(commits, git.CommitInfo{
+		Hash:         "fedcba0987654321",
+		Author:       "John Doe",
+		Date:         time.Date(2025, 5, 20, 18, 30, 0, 0, time.UTC),
+		Message:      "fix: 修复周报",
+		ChangedFiles: []string{"report.go", "html.go"},
+		Insertions:   5,
+		RepoPath:     "web",
+	})
+	if err := generator.GenerateReport("", commits, time.Now(), time.Now()); err != nil {
+		t.Fatalf("生成报告失败: %v", err)
+	}
+
+	want := utf8BOM + "日期,仓库,分支,作者,哈希值,标题,变更文件数,新增行数,删除行数,角色\n" +
+		"2025-05-20 10:00:00,web,main,John Doe,abcdef1234567890,\"feat: 添加周报, 支持\"\"导出\"\"\",1,10,2,作者\n" +
+		"2025-05-20 18:30:00,web,,John Doe,fedcba0987654321,fix: 修复周报,2,5,0,\n" +
+		"2025-05-21 10:00:00,api,,Jane Doe,1234567890abcdef,fix: 修复导出,0,0,0,共同作者\n"
+	if output.String() != want {
+		t.Errorf("CSV明细不正确\n得到: %q\n期望: %q", output.String(), want)
+	}
+
+	wantDaily := utf8BOM + "日期,仓库,提交数,变更文件数,新增行数,删除行数,首次提交,最后提交\n" +
+		"2025-05-20,web,2,2,15,2,10:00,18:30\n" +
+		"2025-05-21,api,1,0,0,0,10:00,10:00\n"
+	if daily.String() != wantDaily {
+		t.Errorf("按天汇总不正确\n得到: %q\n期望: %q", daily.String(), wantDaily)
+	}
+}
+
+// TestGenerateCSVReportFormula 测试以公式字符开头的单元格被转义，避免在表格软件中执行
+func TestGenerateCSVReportFormula(t *testing.T) {
+	var output, daily bytes.Buffer
+	generator := NewGenerator(FormatCSV, &output)
+	generator.DailyOutput = &daily
+
+	commits := []git.CommitInfo{{
+		Hash:     "abcdef1234567890",
+		Author:   "@admin",
+		Date:     time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC),
+		Message:  "=HYPERLINK(\"http://example.com\")",
+		Branches: []string{"-feature"},
+		RepoPath: "+repo",
+	}}
+	if err := generator.GenerateReport("", commits, time.Now(), time.Now()); err != nil {
+		t.Fatalf("生成报告失败: %v", err)
+	}
+
+	want := "2025-05-20 10:00:00,'+repo,'-feature,'@admin,abcdef1234567890,\"'=HYPERLINK(\"\"http://example.com\"\")\",0,0,0,\n"
+	if !strings.HasSuffix(output.String(), want) {
+		t.Errorf("公式字符应被转义\n得到: %q\n期望结尾: %q", output.String(), want)
+	}
+	if !strings.Contains(daily.String(), "2025-05-20,'+repo,1,") {
+		t.Errorf("按天汇总的仓库名也应被转义, 得到: %q", daily.String())
+	}
+}
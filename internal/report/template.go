@@ -0,0 +1,133 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/kway-teow/git-work-log/internal/git"
+)
+
+// Template 用户自定义的报告模板，使用Go模板语法，数据对象见TemplateData。
+// 文件名以.html或.htm结尾（可以再加.tmpl后缀）时使用html/template，自动转义HTML，
+// 其他文件使用text/template原样输出
+type Template struct {
+	name string
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// TemplateData 报告模板的数据对象，在报告数据模型的基础上增加按仓库分组的提交记录，
+// 例如 {{.Title}}、{{range .Repos}}{{.Repo}}{{end}}、{{range .Groups}}{{range .Commits}}{{.Message}}{{end}}{{end}}
+type TemplateData struct {
+	Report
+	SummaryHTML htmltemplate.HTML // 由Markdown转换为HTML的AI总结
+	Groups      []CommitGroup     // 按仓库分组的提交记录
+}
+
+// LoadTemplate 读取并解析报告模板文件
+func LoadTemplate(path string) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取报告模板失败: %w", err)
+	}
+	return ParseTemplate(filepath.Base(path), string(content))
+}
+
+// ParseTemplate 解析报告模板，name用于判断模板类型和显示错误位置
+func ParseTemplate(name, text string) (*Template, error) {
+	// 解析时只需要知道函数名称，执行前再绑定报告的语言
+	funcs := (&Generator{}).templateFuncs()
+
+	tmpl := &Template{name: name}
+	var err error
+	if isHTMLTemplate(name) {
+		tmpl.html, err = htmltemplate.New(name).Funcs(funcs).Parse(text)
+	} else {
+		tmpl.text, err = texttemplate.New(name).Funcs(texttemplate.FuncMap(funcs)).Parse(text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析报告模板失败: %w", err)
+	}
+	return tmpl, nil
+}
+
+// isHTMLTemplate 判断模板是否输出HTML，如 weekly.html 或 weekly.html.tmpl
+func isHTMLTemplate(name string) bool {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(name, ".tmpl")))
+	return ext == ".html" || ext == ".htm"
+}
+
+// execute 使用报告生成器的模板函数渲染模板
+func (t *Template) execute(g *Generator, w io.Writer, data TemplateData) error {
+	var err error
+	if t.html != nil {
+		err = t.html.Funcs(g.templateFuncs()).Execute(w, data)
+	} else {
+		err = t.text.Funcs(texttemplate.FuncMap(g.templateFuncs())).Execute(w, data)
+	}
+	if err != nil {
+		return fmt.Errorf("渲染报告模板 %s 失败: %w", t.name, err)
+	}
+	return nil
+}
+
+// newTemplateData 创建报告模板的数据对象
+func (g *Generator) newTemplateData(summary string, commits []git.CommitInfo, fromDate, toDate time.Time) TemplateData {
+	// 结构化摘要先转换为Markdown，与普通摘要使用同样的方式渲染
+	summaryMarkdown := summary
+	if g.Structured != nil {
+		var b strings.Builder
+		g.writeStructuredMarkdown(&b, g.Structured)
+		summaryMarkdown = b.String()
+	}
+
+	report := g.newReport(summary, commits, fromDate, toDate)
+	return TemplateData{
+		Report:      report,
+		SummaryHTML: renderMarkdown(summaryMarkdown),
+		Groups:      groupByRepo(report.Commits),
+	}
+}
+
+// generateTemplateReport 使用报告模板生成报告
+func (g *Generator) generateTemplateReport(tmpl *Template, summary string, commits []git.CommitInfo, fromDate, toDate time.Time) error {
+	return tmpl.execute(g, g.Output, g.newTemplateData(summary, commits, fromDate, toDate))
+}
+
+// templateFuncs 报告模板中可用的函数
+func (g *Generator) templateFuncs() htmltemplate.FuncMap {
+	return htmltemplate.FuncMap{
+		"t":    g.t,
+		"join": strings.Join,
+		"date": func(value time.Time) string {
+			return value.Format("2006-01-02")
+		},
+		"datetime": func(value time.Time) string {
+			return value.Format("2006-01-02 15:04:05")
+		},
+		// shortHash 返回提交哈希的前8位
+		"shortHash": func(hash string) string {
+			if len(hash) > 8 {
+				return hash[:8]
+			}
+			return hash
+		},
+		// subject 返回提交消息的第一行
+		"subject": func(message string) string {
+			return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+		},
+		"markdown": renderMarkdown,
+		// role 返回筛选的作者在提交中的角色（作者或共同作者），没有按作者筛选时为空
+		"role": func(role git.CommitRole) string {
+			return g.t(roleLabel(role))
+		},
+		// coAuthors 返回逗号分隔的共同作者名称
+		"coAuthors": coAuthorNames,
+	}
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestTemplateReport 测试使用自定义模板生成报告
+func TestTemplateReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		template string
+		want     string
+	}{
+		{
+			name:     "文本模板",
+			file:     "weekly.tmpl",
+			template: `{{.Title}} {{date .From}}~{{date .To}}{{range .Groups}}|{{.Repo}}:{{range .Commits}}{{shortHash .Hash}} {{subject .Message}};{{end}}{{end}}`,
+			want:     "工作周报 2025-05-19~2025-05-25|api:12345678 fix: 修复<导出>;|web:abcdef12 feat: 添加周报功能;",
+		},
+		{
+			name:     "统计和翻译",
+			file:     "stats.txt",
+			template: `{{.TotalCommits}}{{range .Repos}} {{.Repo}}={{.Commits}}{{end}} {{t "提交记录"}}`,
+			want:     "2 api=1 web=1 提交记录",
+		},
+		{
+			name:     "HTML模板自动转义",
+			file:     "weekly.html.tmpl",
+			template: `<div>{{.SummaryHTML}}</div>{{range .Commits}}<p>{{subject .Message}}</p>{{end}}`,
+			want:     "<div><p><strong>周报</strong></p>\n</div><p>feat: 添加周报功能</p><p>fix: 修复&lt;导出&gt;</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.template), 0o644); err != nil {
+				t.Fatalf("写入模板失败: %v", err)
+			}
+			tmpl, err := LoadTemplate(path)
+			if err != nil {
+				t.Fatalf("加载模板失败: %v", err)
+			}
+
+			var output bytes.Buffer
+			generator := NewGenerator(FormatMarkdown, &output)
+			generator.Template = tmpl
+			commits := testCommits()
+			commits[1].Message = "fix: 修复<导出>\n\n详细说明"
+			from := time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)
+			to := time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC)
+			if err := generator.GenerateReport("**周报**", commits, from, to); err != nil {
+				t.Fatalf("生成报告失败: %v", err)
+			}
+
+			if got := output.String(); got != tt.want {
+				t.Errorf("报告内容不正确\n得到: %q\n期望: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTemplateErrors 测试模板语法错误和引用不存在的字段
+func TestTemplateErrors(t *testing.T) {
+	if _, err := ParseTemplate("broken.tmpl", "{{.Title"); err == nil {
+		t.Error("语法错误的模板应返回错误")
+	}
+	if _, err := LoadTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("不存在的模板文件应返回错误")
+	}
+
+	tmpl, err := ParseTemplate("unknown.tmpl", "{{.Unknown}}")
+	if err != nil {
+		t.Fatalf("解析模板失败: %v", err)
+	}
+	generator := NewGenerator(FormatText, &bytes.Buffer{})
+	generator.Template = tmpl
+	if err := generator.GenerateReport("", testCommits(), time.Now(), time.Now()); err == nil {
+		t.Error("引用不存在的字段应返回错误")
+	}
+}
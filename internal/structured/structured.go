@@ -0,0 +1,104 @@
+// Package structured 定义结构化的AI摘要，AI摘要生成器和报告生成器共用这些类型
+package structured
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// Summary 是结构化的AI摘要，由模型以JSON格式返回并在Go中校验
+type Summary struct {
+	Overview     string   `json:"overview"`     // 总体工作概述
+	Achievements []string `json:"achievements"` // 完成的主要工作或成就
+	InProgress   []string `json:"in_progress"`  // 进行中的工作
+	Risks        []string `json:"risks"`        // 风险、问题或需要关注的事项
+	NextSteps    []string `json:"next_steps"`   // 下一步计划
+}
+
+// Section 结构化摘要的一个列表栏目
+type Section struct {
+	Title string
+	Items []string
+}
+
+// Sections 按固定顺序返回结构化摘要的列表栏目，供报告渲染使用。
+// 栏目标题是中文文本，渲染时需要用i18n.Translate翻译为报告语言
+func (s *Summary) Sections() []Section {
+	return []Section{
+		{"主要成果", s.Achievements},
+		{"进行中的工作", s.InProgress},
+		{"风险与问题", s.Risks},
+		{"下一步计划", s.NextSteps},
+	}
+}
+
+// Validate 校验并规范化结构化摘要：去除首尾空白和空条目，概述不能为空
+func (s *Summary) Validate() error {
+	s.Overview = strings.TrimSpace(s.Overview)
+	if s.Overview == "" {
+		return errors.New("结构化摘要缺少overview")
+	}
+
+	s.Achievements = normalizeItems(s.Achievements)
+	s.InProgress = normalizeItems(s.InProgress)
+	s.Risks = normalizeItems(s.Risks)
+	s.NextSteps = normalizeItems(s.NextSteps)
+	return nil
+}
+
+// normalizeItems 去除条目的首尾空白并删除空条目，nil转换为空切片以便JSON输出[]
+func normalizeItems(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Markdown 将结构化摘要渲染为指定语言的Markdown文本，栏目标题按lang翻译
+func (s *Summary) Markdown(lang i18n.Language) string {
+	t := func(text string) string { return i18n.Translate(lang, text) }
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "### %s\n%s\n", t("总体概述"), s.Overview)
+	for _, section := range s.Sections() {
+		fmt.Fprintf(&result, "\n### %s\n", t(section.Title))
+		if len(section.Items) == 0 {
+			fmt.Fprintln(&result, t("- 无"))
+			continue
+		}
+		for _, item := range section.Items {
+			fmt.Fprintf(&result, "- %s\n", item)
+		}
+	}
+	return result.String()
+}
+
+// Parse 解析并校验模型返回的JSON，允许外层包裹```json代码块，不允许未知字段
+func Parse(text string) (*Summary, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+	decoder.DisallowUnknownFields()
+
+	var summary Summary
+	if err := decoder.Decode(&summary); err != nil {
+		return nil, fmt.Errorf("解析结构化摘要失败: %w", err)
+	}
+	if err := summary.Validate(); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
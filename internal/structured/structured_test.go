@@ -0,0 +1,58 @@
+package structured
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kway-teow/git-work-log/internal/i18n"
+)
+
+// TestParse 测试结构化摘要的解析和校验
+func TestParse(t *testing.T) {
+	// 允许代码块包裹，去除空条目
+	summary, err := Parse("```json\n{\"overview\": \" 完成周报功能 \", \"achievements\": [\"周报\", \"  \"], \"in_progress\": [], \"risks\": [], \"next_steps\": [\"导出PDF\"]}\n```")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if summary.Overview != "完成周报功能" || len(summary.Achievements) != 1 || summary.NextSteps[0] != "导出PDF" {
+		t.Errorf("解析结果不正确: %+v", summary)
+	}
+	if summary.InProgress == nil || summary.Risks == nil {
+		t.Error("空栏目应为空切片而不是nil")
+	}
+
+	// 缺少概述、未知字段和非JSON内容都应返回错误
+	invalid := []string{
+		`{"overview": "", "achievements": []}`,
+		`{"overview": "概述", "summary": "未知字段"}`,
+		`本周完成了周报功能`,
+	}
+	for _, text := range invalid {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("应该返回错误: %s", text)
+		}
+	}
+}
+
+// TestMarkdown 测试结构化摘要按语言渲染栏目标题
+func TestMarkdown(t *testing.T) {
+	summary := &Summary{Overview: "Finished the weekly report", Achievements: []string{"weekly report"}}
+	if err := summary.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+
+	zh := summary.Markdown(i18n.Chinese)
+	if !strings.Contains(zh, "### 总体概述\n") || !strings.Contains(zh, "### 风险与问题\n- 无\n") {
+		t.Errorf("中文栏目不正确:\n%s", zh)
+	}
+
+	en := summary.Markdown(i18n.English)
+	if !strings.Contains(en, "### Overview\n") || !strings.Contains(en, "### Risks and Issues\n- None\n") {
+		t.Errorf("英文栏目不正确:\n%s", en)
+	}
+	for _, r := range en {
+		if r >= 0x4e00 && r <= 0x9fff {
+			t.Fatalf("英文摘要不应包含中文:\n%s", en)
+		}
+	}
+}
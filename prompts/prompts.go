@@ -0,0 +1,10 @@
+// Package prompts 包含内置的提示词模板，编译时嵌入到二进制文件中，
+// 因此在任意目录下运行安装后的程序都可以使用basic、detailed和targeted提示词
+package prompts
+
+import "embed"
+
+// FS 内置的提示词模板：basic.txt、detailed.txt和targeted.txt
+//
+//go:embed *.txt
+var FS embed.FS